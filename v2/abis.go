@@ -0,0 +1,37 @@
+package ethcli
+
+// Embedded ABIs, parsed once when the package is loaded.
+var (
+	erc20ABI                       = mustParseABI(openzeppelinERC20Abi)
+	erc20MintBurnABI               = mustParseABI(openzeppelinERC20MintBurnAbleAbi)
+	erc165ABI                      = mustParseABI(customERC721SupportsInterface)
+	erc721ABI                      = mustParseABI(openzeppelinIERC721Abi)
+	erc721ExistsABI                = mustParseABI(customERC721Exists)
+	erc721MintABI                  = mustParseABI(customERC721Mint)
+	erc721MintWithURIABI           = mustParseABI(customERC721MintWithURI)
+	erc721MintWithTokenIdAndURIABI = mustParseABI(customERC721MintWithTokenIdAndURI)
+	erc721BurnableABI              = mustParseABI(openzeppelinERC721BurnableAbi)
+	erc721EnumerableABI            = mustParseABI(openzeppelinERC721EnumerableAbi)
+	erc721MetadataABI              = mustParseABI(openzeppelinERC721MetadataAbi)
+	erc721PausableABI              = mustParseABI(openzeppelinERC721PauseableAbi)
+	erc721URIStorageABI            = mustParseABI(openzeppelinERC721URIStorageAbi)
+	erc1155ABI                     = mustParseABI(openzeppelinIERC1155Abi)
+	erc2981ABI                     = mustParseABI(openzeppelinERC2981Abi)
+	erc4626ABI                     = mustParseABI(openzeppelinIERC4626Abi)
+	weth9ABI                       = mustParseABI(weth9Abi)
+	ownableABI                     = mustParseABI(openzeppelinOwnable2StepAbi)
+	accessControlABI               = mustParseABI(openzeppelinAccessControlAbi)
+	erc20PausableABI               = mustParseABI(openzeppelinERC20PausableAbi)
+	erc20CappedABI                 = mustParseABI(openzeppelinERC20CappedAbi)
+	erc20VotesABI                  = mustParseABI(openzeppelinERC20VotesAbi)
+	eip1271ABI                     = mustParseABI(eip1271Abi)
+	entryPointABI                  = mustParseABI(erc4337EntryPointAbi)
+	simpleAccountABI               = mustParseABI(erc4337SimpleAccountAbi)
+	simpleAccountV07ABI            = mustParseABI(erc4337SimpleAccountV07Abi)
+	permit2ABI                     = mustParseABI(uniswapPermit2Abi)
+	permit2BatchABI                = mustParseABI(uniswapPermit2BatchAbi)
+	safeABI                        = mustParseABI(safeAbi)
+	safeMultiSendABI               = mustParseABI(safeMultiSendAbi)
+	proxyABI                       = mustParseABI(proxyAbi)
+	standardProbeABI               = mustParseABI(standardProbeAbi)
+)
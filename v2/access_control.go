@@ -0,0 +1,127 @@
+package ethcli
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	openzeppelinAccessControlAbi = `[{"inputs":[{"internalType":"bytes32","name":"role","type":"bytes32"},{"internalType":"address","name":"account","type":"address"}],"name":"hasRole","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32","name":"role","type":"bytes32"}],"name":"getRoleAdmin","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32","name":"role","type":"bytes32"},{"internalType":"address","name":"account","type":"address"}],"name":"grantRole","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"bytes32","name":"role","type":"bytes32"},{"internalType":"address","name":"account","type":"address"}],"name":"revokeRole","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"bytes32","name":"role","type":"bytes32"},{"internalType":"address","name":"callerConfirmation","type":"address"}],"name":"renounceRole","outputs":[],"stateMutability":"nonpayable","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"role","type":"bytes32"},{"indexed":true,"internalType":"bytes32","name":"previousAdminRole","type":"bytes32"},{"indexed":true,"internalType":"bytes32","name":"newAdminRole","type":"bytes32"}],"name":"RoleAdminChanged","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"role","type":"bytes32"},{"indexed":true,"internalType":"address","name":"account","type":"address"},{"indexed":true,"internalType":"address","name":"sender","type":"address"}],"name":"RoleGranted","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"role","type":"bytes32"},{"indexed":true,"internalType":"address","name":"account","type":"address"},{"indexed":true,"internalType":"address","name":"sender","type":"address"}],"name":"RoleRevoked","type":"event"}]`
+)
+
+// Well-known OpenZeppelin roles.
+var (
+	DefaultAdminRole = common.Hash{}
+	MinterRole       = RoleHash("MINTER_ROLE")
+	PauserRole       = RoleHash("PAUSER_ROLE")
+	BurnerRole       = RoleHash("BURNER_ROLE")
+	UpgraderRole     = RoleHash("UPGRADER_ROLE")
+)
+
+// RoleHash returns the role identifier keccak256(name), as declared by OpenZeppelin contracts.
+func RoleHash(name string) common.Hash {
+	return crypto.Keccak256Hash([]byte(name))
+}
+
+func AccessControlHasRole(ctx context.Context, cli *ethclient.Client, contract string, role common.Hash, account string, blockNumber *big.Int) (bool, error) {
+	return callResult[bool](ctx, cli, accessControlABI, contract, "hasRole", blockNumber, role, common.HexToAddress(account))
+}
+
+func AccessControlGetRoleAdmin(ctx context.Context, cli *ethclient.Client, contract string, role common.Hash, blockNumber *big.Int) (common.Hash, error) {
+	admin, err := callResult[[32]byte](ctx, cli, accessControlABI, contract, "getRoleAdmin", blockNumber, role)
+	return common.Hash(admin), err
+}
+
+func AccessControlGrantRole(ctx context.Context, cli *ethclient.Client, key string, contract string, role common.Hash, account string) (string, error) {
+	return sendContract(ctx, cli, key, accessControlABI, contract, "grantRole", role, common.HexToAddress(account))
+}
+
+func AccessControlRevokeRole(ctx context.Context, cli *ethclient.Client, key string, contract string, role common.Hash, account string) (string, error) {
+	return sendContract(ctx, cli, key, accessControlABI, contract, "revokeRole", role, common.HexToAddress(account))
+}
+
+// AccessControlRenounceRole gives up role for account, which must be the sender.
+func AccessControlRenounceRole(ctx context.Context, cli *ethclient.Client, key string, contract string, role common.Hash, account string) (string, error) {
+	return sendContract(ctx, cli, key, accessControlABI, contract, "renounceRole", role, common.HexToAddress(account))
+}
+
+func AccessControlGrantRoleData(role common.Hash, account string) ([]byte, error) {
+	return accessControlABI.Pack("grantRole", role, common.HexToAddress(account))
+}
+
+func AccessControlRevokeRoleData(role common.Hash, account string) ([]byte, error) {
+	return accessControlABI.Pack("revokeRole", role, common.HexToAddress(account))
+}
+
+func AccessControlRenounceRoleData(role common.Hash, account string) ([]byte, error) {
+	return accessControlABI.Pack("renounceRole", role, common.HexToAddress(account))
+}
+
+// AccessControlRoleMembers returns the accounts holding role at toBlock, replaying the RoleGranted and
+// RoleRevoked logs of contract since fromBlock (the deployment block, nil for genesis). Members are sorted.
+func AccessControlRoleMembers(ctx context.Context, cli *ethclient.Client, contract string, role common.Hash, fromBlock, toBlock *big.Int) ([]string, error) {
+	members, err := accessControlScan(ctx, cli, contract, []common.Hash{role}, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	return members[role], nil
+}
+
+// AccessControlMembers is AccessControlRoleMembers for every role that was ever granted.
+// Roles without members left are omitted.
+func AccessControlMembers(ctx context.Context, cli *ethclient.Client, contract string, fromBlock, toBlock *big.Int) (map[common.Hash][]string, error) {
+	return accessControlScan(ctx, cli, contract, nil, fromBlock, toBlock)
+}
+
+func accessControlScan(ctx context.Context, cli *ethclient.Client, contract string, roles []common.Hash, fromBlock, toBlock *big.Int) (map[common.Hash][]string, error) {
+	granted := accessControlABI.Events["RoleGranted"].ID
+	revoked := accessControlABI.Events["RoleRevoked"].ID
+	scanner := NewLogScanner(cli, ethereum.FilterQuery{
+		Addresses: []common.Address{common.HexToAddress(contract)},
+		Topics:    [][]common.Hash{{granted, revoked}, roles},
+	})
+
+	state := map[common.Hash]map[common.Address]bool{}
+	err := scanner.Scan(ctx, fromBlock, toBlock, func(from, to uint64, logs []types.Log) error {
+		for _, log := range logs {
+			if log.Removed || len(log.Topics) < 3 {
+				continue
+			}
+			role, account := log.Topics[1], HashToAddress(log.Topics[2])
+			if state[role] == nil {
+				state[role] = map[common.Address]bool{}
+			}
+			switch log.Topics[0] {
+			case granted:
+				state[role][account] = true
+			case revoked:
+				delete(state[role], account)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[common.Hash][]string, len(state))
+	for role, accounts := range state {
+		if len(accounts) == 0 {
+			continue
+		}
+		list := make([]string, 0, len(accounts))
+		for account := range accounts {
+			list = append(list, account.Hex())
+		}
+		sort.Strings(list)
+		members[role] = list
+	}
+	return members, nil
+}
@@ -0,0 +1,97 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func Test_RoleHash(t *testing.T) {
+	want := common.HexToHash("0x9f2df0fed2c77648de5860a4cc508cd0818c85b8b8a1ab4ceeef8d981c8956a6")
+	if MinterRole != want {
+		t.Fatalf("MINTER_ROLE %s, want %s", MinterRole.Hex(), want.Hex())
+	}
+}
+
+func Test_AccessControlRoleMembers(t *testing.T) {
+	var (
+		alice = common.HexToAddress("0x9000000000000000000000000000000000000001")
+		bob   = common.HexToAddress("0x9000000000000000000000000000000000000002")
+		carol = common.HexToAddress("0x9000000000000000000000000000000000000003")
+	)
+	granted := accessControlABI.Events["RoleGranted"].ID
+	revoked := accessControlABI.Events["RoleRevoked"].ID
+	event := func(topic, role common.Hash, account common.Address, block uint64) types.Log {
+		return types.Log{
+			Address:     exampleToken,
+			Topics:      []common.Hash{topic, role, addressTopic(account), addressTopic(exampleAddress)},
+			BlockNumber: block,
+		}
+	}
+	history := []types.Log{
+		event(granted, DefaultAdminRole, exampleAddress, 1),
+		event(granted, MinterRole, alice, 2),
+		event(granted, MinterRole, bob, 3),
+		event(granted, PauserRole, carol, 4),
+		event(revoked, MinterRole, alice, 5),
+		event(revoked, PauserRole, carol, 6),
+		event(granted, MinterRole, carol, 7),
+	}
+
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_getLogs": func(params []json.RawMessage) (interface{}, *mockError) {
+			var query struct {
+				Topics [][]common.Hash `json:"topics"`
+			}
+			_ = json.Unmarshal(params[0], &query)
+			logs := []types.Log{}
+			for _, log := range history {
+				if len(query.Topics) > 1 && len(query.Topics[1]) > 0 && query.Topics[1][0] != log.Topics[1] {
+					continue
+				}
+				logs = append(logs, log)
+			}
+			return logs, nil
+		},
+		"eth_call": mockContracts([]string{openzeppelinAccessControlAbi}, map[string]mockMethod{
+			"hasRole": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{args[0].([32]byte) == MinterRole && args[1].(common.Address) == bob}, nil
+			},
+			"getRoleAdmin": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{[32]byte(DefaultAdminRole)}, nil
+			},
+		}),
+	})
+	cli := mock.client(t)
+	ctx := context.Background()
+
+	minters, err := AccessControlRoleMembers(ctx, cli, exampleToken.Hex(), MinterRole, nil, big.NewInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(minters) != fmt.Sprint([]string{bob.Hex(), carol.Hex()}) {
+		t.Fatalf("unexpected minters %v", minters)
+	}
+
+	members, err := AccessControlMembers(ctx, cli, exampleToken.Hex(), nil, big.NewInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 2 || len(members[DefaultAdminRole]) != 1 || len(members[MinterRole]) != 2 {
+		t.Fatalf("unexpected members %v", members)
+	}
+
+	ok, err := AccessControlHasRole(ctx, cli, exampleToken.Hex(), MinterRole, bob.Hex(), nil)
+	if err != nil || !ok {
+		t.Fatalf("hasRole %v %v", ok, err)
+	}
+	admin, err := AccessControlGetRoleAdmin(ctx, cli, exampleToken.Hex(), MinterRole, nil)
+	if err != nil || admin != DefaultAdminRole {
+		t.Fatalf("getRoleAdmin %s %v", admin.Hex(), err)
+	}
+}
@@ -0,0 +1,79 @@
+package ethcli
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	weth9Abi = `[{"inputs":[],"name":"deposit","outputs":[],"stateMutability":"payable","type":"function"},{"inputs":[{"internalType":"uint256","name":"wad","type":"uint256"}],"name":"withdraw","outputs":[],"stateMutability":"nonpayable","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"dst","type":"address"},{"indexed":false,"internalType":"uint256","name":"wad","type":"uint256"}],"name":"Deposit","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"src","type":"address"},{"indexed":false,"internalType":"uint256","name":"wad","type":"uint256"}],"name":"Withdrawal","type":"event"}]`
+)
+
+// NativeAsset is the placeholder address commonly used for the chain's native currency.
+// An empty asset is treated the same way.
+const NativeAsset = "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE"
+
+// IsNativeAsset reports whether asset denotes the native currency rather than an ERC20 token.
+func IsNativeAsset(asset string) bool {
+	return asset == "" || strings.EqualFold(asset, NativeAsset)
+}
+
+// Transfer sends amount of asset to to, as a plain value transfer for the native currency or an ERC20
+// transfer otherwise. amount is in the smallest unit (wei for the native currency).
+func Transfer(ctx context.Context, cli *ethclient.Client, key, asset, to, amount string) (string, error) {
+	if !IsNativeAsset(asset) {
+		return ERC20Transfer(ctx, cli, asset, key, to, amount)
+	}
+	if v, ok := new(big.Int).SetString(amount, 10); !ok || v.Sign() < 0 {
+		return "", errors.New("invalid value:" + amount)
+	}
+	return SendLegacyTx(ctx, cli, key, &to, amount, "", "0", 0)
+}
+
+// BalanceOf returns the balance of holder in asset: the account balance for the native currency or the
+// ERC20 balance otherwise.
+func BalanceOf(ctx context.Context, cli *ethclient.Client, asset, holder string, blockNumber *big.Int) (*big.Int, error) {
+	if IsNativeAsset(asset) {
+		return cli.BalanceAt(ctx, common.HexToAddress(holder), blockNumber)
+	}
+	return ERC20BalanceOf(ctx, cli, asset, holder, blockNumber)
+}
+
+// WETHDeposit wraps amount wei of the native currency into the WETH9 contract weth.
+func WETHDeposit(ctx context.Context, cli *ethclient.Client, key, weth, amount string) (string, error) {
+	if v, ok := new(big.Int).SetString(amount, 10); !ok || v.Sign() < 0 {
+		return "", errors.New("invalid value:" + amount)
+	}
+	data, err := WETHDepositData()
+	if err != nil {
+		return "", err
+	}
+	return SendLegacyTx(ctx, cli, key, &weth, amount, BytesToHex(data), "0", 0)
+}
+
+// WETHWithdraw unwraps amount of weth back into the native currency.
+func WETHWithdraw(ctx context.Context, cli *ethclient.Client, key, weth, amount string) (string, error) {
+	wad, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return "", errors.New("invalid value:" + amount)
+	}
+	return sendContract(ctx, cli, key, weth9ABI, weth, "withdraw", wad)
+}
+
+// WETHDepositData returns the calldata of deposit(); the amount to wrap is the value of the transaction.
+func WETHDepositData() ([]byte, error) {
+	return weth9ABI.Pack("deposit")
+}
+
+func WETHWithdrawData(amount string) ([]byte, error) {
+	wad, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return nil, errors.New("invalid value:" + amount)
+	}
+	return weth9ABI.Pack("withdraw", wad)
+}
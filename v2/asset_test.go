@@ -0,0 +1,75 @@
+package ethcli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func Test_Transfer(t *testing.T) {
+	var (
+		to   = common.HexToAddress("0x8000000000000000000000000000000000000001")
+		weth = common.HexToAddress("0x8000000000000000000000000000000000000002")
+	)
+	pool := newMockTxPool(nil)
+	rpc := newMockRPC(t, pool.handlers())
+	cli := rpc.client(t)
+	ctx := context.Background()
+
+	if _, err := Transfer(ctx, cli, exampleKey, NativeAsset, to.Hex(), "1000"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Transfer(ctx, cli, exampleKey, exampleToken.Hex(), to.Hex(), "2000"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WETHDeposit(ctx, cli, exampleKey, weth.Hex(), "3000"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Transfer(ctx, cli, exampleKey, "", to.Hex(), "1e18"); err == nil {
+		t.Fatal("expected error for invalid amount")
+	}
+
+	txs := pool.transactions()
+	if len(txs) != 3 {
+		t.Fatalf("%d transactions sent, want 3", len(txs))
+	}
+	if *txs[0].To() != to || txs[0].Value().Int64() != 1000 || len(txs[0].Data()) != 0 {
+		t.Fatalf("native transfer to %s value %s data %x", txs[0].To(), txs[0].Value(), txs[0].Data())
+	}
+	transfer, _ := ERC20TransferData(to.Hex(), "2000")
+	if *txs[1].To() != exampleToken || txs[1].Value().Sign() != 0 || !bytes.Equal(txs[1].Data(), transfer) {
+		t.Fatalf("token transfer to %s value %s data %x", txs[1].To(), txs[1].Value(), txs[1].Data())
+	}
+	deposit, _ := WETHDepositData()
+	if *txs[2].To() != weth || txs[2].Value().Int64() != 3000 || !bytes.Equal(txs[2].Data(), deposit) {
+		t.Fatalf("weth deposit to %s value %s data %x", txs[2].To(), txs[2].Value(), txs[2].Data())
+	}
+}
+
+func Test_BalanceOf(t *testing.T) {
+	rpc := newMockRPC(t, map[string]mockHandler{
+		"eth_getBalance": func(params []json.RawMessage) (interface{}, *mockError) {
+			return (*hexutil.Big)(big.NewInt(7)), nil
+		},
+		"eth_call": mockContracts([]string{openzeppelinERC20Abi}, map[string]mockMethod{
+			"balanceOf": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{big.NewInt(9)}, nil
+			},
+		}),
+	})
+	cli := rpc.client(t)
+
+	native, err := BalanceOf(context.Background(), cli, "", exampleAddress.Hex(), nil)
+	if err != nil || native.Int64() != 7 {
+		t.Fatalf("native balance %s %v", native, err)
+	}
+	token, err := BalanceOf(context.Background(), cli, exampleToken.Hex(), exampleAddress.Hex(), nil)
+	if err != nil || token.Int64() != 9 {
+		t.Fatalf("token balance %s %v", token, err)
+	}
+}
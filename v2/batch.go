@@ -0,0 +1,54 @@
+package ethcli
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BatchCallSize is the number of eth_call requests sent in one JSON-RPC batch.
+var BatchCallSize = 100
+
+// BatchCallResult is the outcome of one call of a batch. A reverted call only fails its own result.
+type BatchCallResult struct {
+	Data []byte
+	Err  error
+}
+
+// BatchCallContract executes msgs at blockNumber using JSON-RPC batches of BatchCallSize calls.
+// The returned error is set when a whole batch fails; failures of single calls are in the results.
+func BatchCallContract(ctx context.Context, cli *ethclient.Client, msgs []ethereum.CallMsg, blockNumber *big.Int) ([]BatchCallResult, error) {
+	size := BatchCallSize
+	if size <= 0 {
+		size = 100
+	}
+	results := make([]BatchCallResult, len(msgs))
+	for start := 0; start < len(msgs); start += size {
+		end := min(start+size, len(msgs))
+		elems := make([]rpc.BatchElem, 0, end-start)
+		outs := make([]hexutil.Bytes, end-start)
+		for i, msg := range msgs[start:end] {
+			arg := map[string]interface{}{"to": msg.To, "data": hexutil.Bytes(msg.Data)}
+			if msg.From != (common.Address{}) {
+				arg["from"] = msg.From
+			}
+			elems = append(elems, rpc.BatchElem{
+				Method: "eth_call",
+				Args:   []interface{}{arg, toBlockNumArg(blockNumber)},
+				Result: &outs[i],
+			})
+		}
+		if err := cli.Client().BatchCallContext(ctx, elems); err != nil {
+			return nil, err
+		}
+		for i, elem := range elems {
+			results[start+i] = BatchCallResult{Data: outs[i], Err: elem.Error}
+		}
+	}
+	return results, nil
+}
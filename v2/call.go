@@ -0,0 +1,95 @@
+package ethcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ErrUnexpectedResult is returned when a call returns fewer values than expected or values of another type.
+var ErrUnexpectedResult = errors.New("unexpected call result")
+
+// mustParseABI parses an embedded ABI at package initialisation.
+func mustParseABI(abiJSON string) *abi.ABI {
+	ins, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		panic(err)
+	}
+	return &ins
+}
+
+// callContract packs method, calls contract at blockNumber and unpacks the outputs.
+func callContract(ctx context.Context, cli *ethclient.Client, ins *abi.ABI, contract string, method string, blockNumber *big.Int, args ...interface{}) ([]interface{}, error) {
+	data, err := ins.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	to := common.HexToAddress(contract)
+	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
+		To:   &to,
+		Data: data,
+	}, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := ins.Unpack(method, bz)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrUnexpectedResult, method, err)
+	}
+	return results, nil
+}
+
+// isCallFailure reports whether err was caused by the contract, a revert or undecodable output,
+// rather than by the node or the transport.
+func isCallFailure(err error) bool {
+	return errors.Is(err, ErrUnexpectedResult) || isExecutionReverted(err)
+}
+
+// ignoreCallFailure returns nil for errors matched by isCallFailure and err otherwise.
+func ignoreCallFailure(err error) error {
+	if err == nil || isCallFailure(err) {
+		return nil
+	}
+	return err
+}
+
+// callResult is callContract for methods with a single output of type T.
+func callResult[T any](ctx context.Context, cli *ethclient.Client, ins *abi.ABI, contract string, method string, blockNumber *big.Int, args ...interface{}) (T, error) {
+	results, err := callContract(ctx, cli, ins, contract, method, blockNumber, args...)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return resultAt[T](method, results, 0)
+}
+
+// resultAt returns output i of method as T, failing instead of panicking on a short or mistyped result.
+func resultAt[T any](method string, results []interface{}, i int) (T, error) {
+	var zero T
+	if i >= len(results) {
+		return zero, fmt.Errorf("%w: %s returned %d values, want at least %d", ErrUnexpectedResult, method, len(results), i+1)
+	}
+	v, ok := results[i].(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: %s value %d is %T, want %T", ErrUnexpectedResult, method, i, results[i], zero)
+	}
+	return v, nil
+}
+
+// sendContract packs method and sends it to contract as a legacy transaction signed by key.
+func sendContract(ctx context.Context, cli *ethclient.Client, key string, ins *abi.ABI, contract string, method string, args ...interface{}) (string, error) {
+	data, err := ins.Pack(method, args...)
+	if err != nil {
+		return "", err
+	}
+	return SendLegacyTx(ctx, cli, key, &contract, "0", BytesToHex(data), "0", 0)
+}
@@ -0,0 +1,81 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_resultAt(t *testing.T) {
+	results := []interface{}{big.NewInt(1), "x"}
+	if v, err := resultAt[*big.Int]("m", results, 0); err != nil || v.Int64() != 1 {
+		t.Fatalf("unexpected %v %v", v, err)
+	}
+	if _, err := resultAt[*big.Int]("m", results, 1); !errors.Is(err, ErrUnexpectedResult) {
+		t.Fatalf("expected type error, got %v", err)
+	}
+	if _, err := resultAt[string]("m", results, 2); !errors.Is(err, ErrUnexpectedResult) {
+		t.Fatalf("expected length error, got %v", err)
+	}
+}
+
+func Test_ERC20BalanceOfBadResult(t *testing.T) {
+	result := "0x"
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_call": func(params []json.RawMessage) (interface{}, *mockError) {
+			return result, nil
+		},
+	})
+	cli := mock.client(t)
+
+	// an account without code returns no data
+	if _, err := ERC20BalanceOf(context.Background(), cli, exampleToken.Hex(), exampleAddress.Hex(), nil); !errors.Is(err, ErrUnexpectedResult) {
+		t.Fatalf("got %v, want ErrUnexpectedResult for empty result", err)
+	}
+	result = "0x" + strings.Repeat("00", 31) + "2a"
+	if v, err := ERC20BalanceOf(context.Background(), cli, exampleToken.Hex(), exampleAddress.Hex(), nil); err != nil || v.Int64() != 42 {
+		t.Fatalf("unexpected %v %v", v, err)
+	}
+}
+
+var benchBalance = common.LeftPadBytes(big.NewInt(1e18).Bytes(), 32)
+
+// BenchmarkBalanceOfParsePerCall is the encode/decode cost of a balanceOf read when the ABI is parsed on every call.
+func BenchmarkBalanceOfParsePerCall(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		ins, err := abi.JSON(strings.NewReader(openzeppelinERC20Abi))
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := ins.Pack("balanceOf", exampleAddress); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := ins.Unpack("balanceOf", benchBalance); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkBalanceOfSharedABI is the same work with the ABI parsed once.
+func BenchmarkBalanceOfSharedABI(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := erc20ABI.Pack("balanceOf", exampleAddress); err != nil {
+			b.Fatal(err)
+		}
+		results, err := erc20ABI.Unpack("balanceOf", benchBalance)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := resultAt[*big.Int]("balanceOf", results, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
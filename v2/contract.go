@@ -0,0 +1,195 @@
+package ethcli
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Contract calls any contract described by an ABI JSON. Arguments may be passed as the Go types
+// go-ethereum expects or as strings/JSON, see ConvertArgument.
+type Contract struct {
+	Address common.Address
+	ABI     abi.ABI
+	Client  *ethclient.Client
+}
+
+// ContractEvent is a decoded log with indexed and data fields merged by name.
+type ContractEvent struct {
+	Name   string
+	Fields map[string]interface{}
+	Log    types.Log
+}
+
+func NewContract(cli *ethclient.Client, address string, abiJSON string) (*Contract, error) {
+	if !common.IsHexAddress(address) {
+		return nil, fmt.Errorf("invalid address %q", address)
+	}
+	ins, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, err
+	}
+	return &Contract{Address: common.HexToAddress(address), ABI: ins, Client: cli}, nil
+}
+
+// Pack encodes the calldata of method, converting args to the method's input types.
+func (c *Contract) Pack(method string, args ...interface{}) ([]byte, error) {
+	m, ok := c.ABI.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("method %s not found", method)
+	}
+	converted, err := ConvertArguments(m.Inputs, args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	return c.ABI.Pack(method, converted...)
+}
+
+func (c *Contract) call(ctx context.Context, blockNumber *big.Int, method string, args []interface{}) ([]byte, error) {
+	data, err := c.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return c.Client.CallContract(ctx, ethereum.CallMsg{
+		To:   &c.Address,
+		Data: data,
+	}, blockNumber)
+}
+
+// Call executes a read-only method at blockNumber and returns its outputs in order.
+func (c *Contract) Call(ctx context.Context, blockNumber *big.Int, method string, args ...interface{}) ([]interface{}, error) {
+	bz, err := c.call(ctx, blockNumber, method, args)
+	if err != nil {
+		return nil, err
+	}
+	return c.ABI.Unpack(method, bz)
+}
+
+// CallMap is like Call but returns the outputs by name; unnamed outputs are keyed by their position.
+func (c *Contract) CallMap(ctx context.Context, blockNumber *big.Int, method string, args ...interface{}) (map[string]interface{}, error) {
+	results, err := c.Call(ctx, blockNumber, method, args...)
+	if err != nil {
+		return nil, err
+	}
+	outputs := c.ABI.Methods[method].Outputs
+	m := make(map[string]interface{}, len(results))
+	for i, v := range results {
+		name := outputs[i].Name
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		m[name] = v
+	}
+	return m, nil
+}
+
+// CallInto is like Call but copies the outputs into out, a pointer to a struct with fields
+// named after the outputs or, for a single output, a pointer to its type.
+func (c *Contract) CallInto(ctx context.Context, blockNumber *big.Int, out interface{}, method string, args ...interface{}) error {
+	bz, err := c.call(ctx, blockNumber, method, args)
+	if err != nil {
+		return err
+	}
+	return c.ABI.UnpackIntoInterface(out, method, bz)
+}
+
+// Transact sends method with amount wei attached as a legacy transaction signed by key.
+func (c *Contract) Transact(ctx context.Context, key string, amount string, method string, args ...interface{}) (string, error) {
+	data, err := c.Pack(method, args...)
+	if err != nil {
+		return "", err
+	}
+	to := c.Address.Hex()
+	return SendLegacyTx(ctx, c.Client, key, &to, amount, BytesToHex(data), "0", 0)
+}
+
+// DecodeEvent decodes a log of one of the contract's events.
+func (c *Contract) DecodeEvent(log types.Log) (*ContractEvent, error) {
+	ev, err := c.eventOf(log)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	if len(log.Data) > 0 {
+		if err := c.ABI.UnpackIntoMap(fields, ev.Name, log.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(fields, indexedArguments(ev.Inputs), log.Topics[1:]); err != nil {
+		return nil, err
+	}
+	return &ContractEvent{Name: ev.Name, Fields: fields, Log: log}, nil
+}
+
+// DecodeEventInto decodes a log into out, a pointer to a struct with fields named after the event arguments.
+func (c *Contract) DecodeEventInto(out interface{}, log types.Log) error {
+	ev, err := c.eventOf(log)
+	if err != nil {
+		return err
+	}
+	if len(log.Data) > 0 {
+		if err := c.ABI.UnpackIntoInterface(out, ev.Name, log.Data); err != nil {
+			return err
+		}
+	}
+	return abi.ParseTopics(out, indexedArguments(ev.Inputs), log.Topics[1:])
+}
+
+// FilterEvents returns the decoded event logs of the contract in [fromBlock, toBlock].
+// It fails on the first log that does not match the ABI of the event.
+func (c *Contract) FilterEvents(ctx context.Context, event string, fromBlock, toBlock *big.Int) ([]ContractEvent, error) {
+	ev, ok := c.ABI.Events[event]
+	if !ok {
+		return nil, fmt.Errorf("event %s not found", event)
+	}
+	logs, err := c.Client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: []common.Address{c.Address},
+		Topics:    [][]common.Hash{{ev.ID}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	events := make([]ContractEvent, 0, len(logs))
+	for _, log := range logs {
+		decoded, err := c.DecodeEvent(log)
+		if err != nil {
+			return nil, fmt.Errorf("log %d of tx %s: %w", log.Index, log.TxHash.Hex(), err)
+		}
+		events = append(events, *decoded)
+	}
+	return events, nil
+}
+
+func (c *Contract) eventOf(log types.Log) (*abi.Event, error) {
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("log without topics")
+	}
+	ev, err := c.ABI.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(log.Topics)-1 != len(indexedArguments(ev.Inputs)) {
+		return nil, fmt.Errorf("%s: expected %d indexed topics, got %d", ev.Name, len(indexedArguments(ev.Inputs)), len(log.Topics)-1)
+	}
+	return ev, nil
+}
+
+func indexedArguments(args abi.Arguments) abi.Arguments {
+	var indexed abi.Arguments
+	for _, arg := range args {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	return indexed
+}
@@ -0,0 +1,292 @@
+package ethcli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ConvertArguments converts args to the Go types go-ethereum packs for inputs, see ConvertArgument.
+func ConvertArguments(inputs abi.Arguments, args []interface{}) ([]interface{}, error) {
+	if len(args) != len(inputs) {
+		return nil, fmt.Errorf("argument count mismatch: got %d for %d", len(args), len(inputs))
+	}
+	out := make([]interface{}, len(args))
+	for i, arg := range args {
+		v, err := ConvertArgument(inputs[i].Type, arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument %d (%s): %w", i, inputs[i].Name, err)
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+// ConvertArgument converts v to the Go type of typ. Values already of that type are returned as is.
+// Strings are parsed by type: decimal or 0x integers, hex addresses and bytes, true/false, and
+// JSON for arrays and tuples (objects by component name or arrays by position). Go integers,
+// json.Number, []interface{} and map[string]interface{} values are converted as well.
+func ConvertArgument(typ abi.Type, v interface{}) (interface{}, error) {
+	target := typ.GetType()
+	if v != nil && reflect.TypeOf(v) == target {
+		return v, nil
+	}
+	value, err := convertValue(typ, v)
+	if err != nil {
+		return nil, err
+	}
+	return value.Interface(), nil
+}
+
+func convertValue(typ abi.Type, v interface{}) (reflect.Value, error) {
+	target := typ.GetType()
+	if v != nil && reflect.TypeOf(v) == target {
+		return reflect.ValueOf(v), nil
+	}
+	if rv := reflect.ValueOf(v); v != nil && rv.Type().ConvertibleTo(target) && rv.Kind() == target.Kind() && rv.Kind() != reflect.String {
+		return rv.Convert(target), nil
+	}
+
+	switch typ.T {
+	case abi.IntTy, abi.UintTy:
+		n, err := toBigInt(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if typ.T == abi.UintTy && n.Sign() < 0 {
+			return reflect.Value{}, fmt.Errorf("negative value %s for %s", n, typ)
+		}
+		if typ.T == abi.UintTy && n.BitLen() > typ.Size {
+			return reflect.Value{}, fmt.Errorf("value %s overflows %s", n, typ)
+		}
+		if limit := new(big.Int).Lsh(big.NewInt(1), uint(typ.Size-1)); typ.T == abi.IntTy && (n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0) {
+			return reflect.Value{}, fmt.Errorf("value %s overflows %s", n, typ)
+		}
+		if target == reflect.TypeOf((*big.Int)(nil)) {
+			return reflect.ValueOf(n), nil
+		}
+		out := reflect.New(target).Elem()
+		if typ.T == abi.IntTy {
+			out.SetInt(n.Int64())
+		} else {
+			out.SetUint(n.Uint64())
+		}
+		return out, nil
+	case abi.BoolTy:
+		s, err := toText(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b), nil
+	case abi.StringTy:
+		s, err := toText(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(s), nil
+	case abi.AddressTy:
+		s, err := toText(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if !common.IsHexAddress(s) {
+			return reflect.Value{}, fmt.Errorf("invalid address %q", s)
+		}
+		return reflect.ValueOf(common.HexToAddress(s)), nil
+	case abi.BytesTy:
+		bz, err := toBytes(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(bz), nil
+	case abi.FixedBytesTy:
+		bz, err := toBytes(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if len(bz) != typ.Size {
+			return reflect.Value{}, fmt.Errorf("expected %d bytes for %s, got %d", typ.Size, typ, len(bz))
+		}
+		out := reflect.New(target).Elem()
+		reflect.Copy(out, reflect.ValueOf(bz))
+		return out, nil
+	case abi.SliceTy, abi.ArrayTy:
+		items, err := toList(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		var out reflect.Value
+		if typ.T == abi.ArrayTy {
+			if len(items) != typ.Size {
+				return reflect.Value{}, fmt.Errorf("expected %d elements for %s, got %d", typ.Size, typ, len(items))
+			}
+			out = reflect.New(target).Elem()
+		} else {
+			out = reflect.MakeSlice(target, len(items), len(items))
+		}
+		for i, item := range items {
+			elem, err := convertValue(*typ.Elem, item)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %w", i, err)
+			}
+			out.Index(i).Set(elem)
+		}
+		return out, nil
+	case abi.TupleTy:
+		items, err := toTupleItems(typ, v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out := reflect.New(target).Elem()
+		for i, elem := range typ.TupleElems {
+			field, err := convertValue(*elem, items[i])
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("field %s: %w", typ.TupleRawNames[i], err)
+			}
+			out.Field(i).Set(field)
+		}
+		return out, nil
+	}
+	return reflect.Value{}, fmt.Errorf("cannot convert %T to %s", v, typ)
+}
+
+func toText(v interface{}) (string, error) {
+	switch x := v.(type) {
+	case string:
+		return strings.TrimSpace(x), nil
+	case json.Number:
+		return x.String(), nil
+	case bool:
+		return strconv.FormatBool(x), nil
+	case common.Address:
+		return x.Hex(), nil
+	}
+	return "", fmt.Errorf("cannot convert %T", v)
+}
+
+func toBigInt(v interface{}) (*big.Int, error) {
+	switch x := v.(type) {
+	case *big.Int:
+		return new(big.Int).Set(x), nil
+	case big.Int:
+		return new(big.Int).Set(&x), nil
+	case float64:
+		if x != float64(int64(x)) {
+			return nil, fmt.Errorf("non-integer value %v", x)
+		}
+		return big.NewInt(int64(x)), nil
+	}
+	if rv := reflect.ValueOf(v); v != nil {
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return big.NewInt(rv.Int()), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return new(big.Int).SetUint64(rv.Uint()), nil
+		}
+	}
+	s, err := toText(v)
+	if err != nil {
+		return nil, err
+	}
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer %q", s)
+	}
+	return n, nil
+}
+
+func toBytes(v interface{}) ([]byte, error) {
+	switch x := v.(type) {
+	case []byte:
+		return x, nil
+	case common.Hash:
+		return x.Bytes(), nil
+	}
+	if rv := reflect.ValueOf(v); v != nil && rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+		bz := make([]byte, rv.Len())
+		reflect.Copy(reflect.ValueOf(bz), rv)
+		return bz, nil
+	}
+	s, err := toText(v)
+	if err != nil {
+		return nil, err
+	}
+	return hexutil.Decode(s)
+}
+
+func toList(v interface{}) ([]interface{}, error) {
+	switch x := v.(type) {
+	case []interface{}:
+		return x, nil
+	case string:
+		var items []interface{}
+		if err := decodeJSON(x, &items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+	rv := reflect.ValueOf(v)
+	if v == nil || (rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array) {
+		return nil, fmt.Errorf("cannot convert %T to a list", v)
+	}
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items, nil
+}
+
+func toTupleItems(typ abi.Type, v interface{}) ([]interface{}, error) {
+	if s, ok := v.(string); ok {
+		var decoded interface{}
+		if err := decodeJSON(s, &decoded); err != nil {
+			return nil, err
+		}
+		v = decoded
+	}
+	if m, ok := v.(map[string]interface{}); ok {
+		items := make([]interface{}, len(typ.TupleElems))
+		for i, name := range typ.TupleRawNames {
+			item, ok := m[name]
+			if !ok {
+				return nil, fmt.Errorf("missing field %s", name)
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+	if rv := reflect.ValueOf(v); v != nil && rv.Kind() == reflect.Struct {
+		items := make([]interface{}, rv.NumField())
+		for i := range items {
+			items[i] = rv.Field(i).Interface()
+		}
+		v = items
+	}
+	items, err := toList(v)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) != len(typ.TupleElems) {
+		return nil, fmt.Errorf("expected %d fields for tuple, got %d", len(typ.TupleElems), len(items))
+	}
+	return items, nil
+}
+
+// decodeJSON keeps numbers as json.Number so large integers survive.
+func decodeJSON(s string, out interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader([]byte(s)))
+	dec.UseNumber()
+	return dec.Decode(out)
+}
@@ -0,0 +1,169 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const testContractAbi = `[
+{"type":"function","name":"submit","stateMutability":"nonpayable","inputs":[{"name":"order","type":"tuple","components":[{"name":"maker","type":"address"},{"name":"amounts","type":"uint256[]"},{"name":"salt","type":"bytes32"}]},{"name":"deadline","type":"uint64"},{"name":"flag","type":"bool"}],"outputs":[]},
+{"type":"function","name":"info","stateMutability":"view","inputs":[{"name":"id","type":"int8"}],"outputs":[{"name":"owner","type":"address"},{"name":"score","type":"int256"}]},
+{"type":"function","name":"count","stateMutability":"view","inputs":[],"outputs":[{"name":"","type":"uint256"}]},
+{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]}
+]`
+
+func Test_ContractPack(t *testing.T) {
+	c, err := NewContract(nil, exampleToken.Hex(), testContractAbi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	salt := common.HexToHash("0x01")
+	native, err := c.ABI.Pack("submit", struct {
+		Maker   common.Address
+		Amounts []*big.Int
+		Salt    [32]byte
+	}{exampleAddress, []*big.Int{big.NewInt(1), big.NewInt(2)}, salt}, uint64(100), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fromJSON, err := c.Pack("submit", `{"maker":"`+exampleAddress.Hex()+`","amounts":["1","0x2"],"salt":"`+salt.Hex()+`"}`, "100", "true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromArray, err := c.Pack("submit", []interface{}{exampleAddress.Hex(), "[1, 2]", salt}, 100, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if BytesToHex(fromJSON) != BytesToHex(native) || BytesToHex(fromArray) != BytesToHex(native) {
+		t.Fatal("converted arguments pack differently")
+	}
+
+	for _, args := range [][]interface{}{
+		{"1"},
+		{"{}", "1", "true"},
+		{`{"maker":"0x12","amounts":[],"salt":"0x"}`, "1", "true"},
+		{`{"maker":"` + exampleAddress.Hex() + `","amounts":[],"salt":"` + salt.Hex() + `"}`, "18446744073709551616", "true"},
+		{`{"maker":"` + exampleAddress.Hex() + `","amounts":["-1"],"salt":"` + salt.Hex() + `"}`, "1", "true"},
+	} {
+		if _, err := c.Pack("submit", args...); err == nil {
+			t.Fatalf("expected error for %v", args)
+		}
+	}
+	if _, err := c.Pack("info", "-129"); err == nil {
+		t.Fatal("expected int8 overflow")
+	}
+}
+
+func Test_ContractCall(t *testing.T) {
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_call": mockContracts([]string{testContractAbi}, map[string]mockMethod{
+			"info": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{exampleAddress, big.NewInt(int64(args[0].(int8)) * 2)}, nil
+			},
+			"count": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{big.NewInt(3)}, nil
+			},
+		}),
+	})
+	c, err := NewContract(mock.client(t), exampleToken.Hex(), testContractAbi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	m, err := c.CallMap(ctx, nil, "info", "-7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["owner"] != exampleAddress || m["score"].(*big.Int).Int64() != -14 {
+		t.Fatalf("unexpected result %v", m)
+	}
+
+	var out struct {
+		Owner common.Address
+		Score *big.Int
+	}
+	if err := c.CallInto(ctx, nil, &out, "info", 21); err != nil {
+		t.Fatal(err)
+	}
+	if out.Owner != exampleAddress || out.Score.Int64() != 42 {
+		t.Fatalf("unexpected result %+v", out)
+	}
+
+	if m, err := c.CallMap(ctx, nil, "count"); err != nil || m["0"].(*big.Int).Int64() != 3 {
+		t.Fatalf("unexpected result %v %v", m, err)
+	}
+	count := new(big.Int)
+	if err := c.CallInto(ctx, nil, &count, "count"); err != nil || count.Int64() != 3 {
+		t.Fatalf("unexpected result %v %v", count, err)
+	}
+}
+
+func Test_ContractDecodeEvent(t *testing.T) {
+	c, _ := NewContract(nil, exampleToken.Hex(), testContractAbi)
+	other := common.HexToAddress("0x5555555555555555555555555555555555555555")
+	log := types.Log{
+		Address: exampleToken,
+		Topics:  []common.Hash{transferTopic, addressTopic(exampleAddress), addressTopic(other)},
+		Data:    common.LeftPadBytes(big.NewInt(42).Bytes(), 32),
+	}
+
+	ev, err := c.DecodeEvent(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ev.Name != "Transfer" || ev.Fields["from"] != exampleAddress || ev.Fields["to"] != other || ev.Fields["value"].(*big.Int).Int64() != 42 {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+
+	var out struct {
+		From  common.Address
+		To    common.Address
+		Value *big.Int
+	}
+	if err := c.DecodeEventInto(&out, log); err != nil {
+		t.Fatal(err)
+	}
+	if out.From != exampleAddress || out.To != other || out.Value.Int64() != 42 {
+		t.Fatalf("unexpected event %+v", out)
+	}
+
+	// ERC721 Transfer shares the signature but not the topic layout
+	log.Topics = append(log.Topics, common.Hash{})
+	if _, err := c.DecodeEvent(log); err == nil {
+		t.Fatal("expected topic count error")
+	}
+}
+
+func Test_ContractFilterEvents(t *testing.T) {
+	valid := types.Log{
+		Address: exampleToken,
+		Topics:  []common.Hash{transferTopic, addressTopic(exampleAddress), addressTopic(exampleToken)},
+		Data:    common.LeftPadBytes(big.NewInt(42).Bytes(), 32),
+	}
+	// ERC721 Transfer shares the signature but not the topic layout
+	invalid := valid
+	invalid.Topics = append(append([]common.Hash{}, valid.Topics...), common.Hash{})
+	logs := []types.Log{valid}
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_getLogs": func(params []json.RawMessage) (interface{}, *mockError) {
+			return logs, nil
+		},
+	})
+	c, _ := NewContract(mock.client(t), exampleToken.Hex(), testContractAbi)
+
+	events, err := c.FilterEvents(context.Background(), "Transfer", nil, nil)
+	if err != nil || len(events) != 1 || events[0].Fields["value"].(*big.Int).Int64() != 42 {
+		t.Fatalf("unexpected events %+v %v", events, err)
+	}
+	logs = append(logs, invalid)
+	if events, err := c.FilterEvents(context.Background(), "Transfer", nil, nil); err == nil || events != nil {
+		t.Fatalf("expected decode error, got %+v %v", events, err)
+	}
+}
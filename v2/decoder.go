@@ -0,0 +1,440 @@
+package ethcli
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+//go:embed signatures/signatures.txt
+var signatureDatabase string
+
+var (
+	ErrUnknownSelector = errors.New("unknown function selector")
+	ErrUnknownEvent    = errors.New("unknown event topic")
+)
+
+// DecodedArg is one decoded parameter.
+type DecodedArg struct {
+	Name  string
+	Type  string
+	Value interface{}
+}
+
+// DecodedCall is decoded calldata.
+type DecodedCall struct {
+	Name      string
+	Signature string
+	Selector  [4]byte
+	Args      []DecodedArg
+	method    abi.Method
+}
+
+// DecodedLog is a decoded event log.
+type DecodedLog struct {
+	Name      string
+	Signature string
+	Args      []DecodedArg
+}
+
+// String renders the call as name(arg: value, ...).
+func (c *DecodedCall) String() string {
+	return c.Name + "(" + formatDecodedArgs(c.Args) + ")"
+}
+
+// String renders the log as Name(arg: value, ...).
+func (l *DecodedLog) String() string {
+	return l.Name + "(" + formatDecodedArgs(l.Args) + ")"
+}
+
+// Decoder decodes calldata, return data and logs. Registered ABIs are tried first, then the
+// signatures added with RegisterSignature and the embedded signature database.
+type Decoder struct {
+	mu           sync.RWMutex
+	methods      map[[4]byte][]abi.Method
+	events       map[common.Hash][]abi.Event
+	sigMethods   map[[4]byte][]abi.Method
+	sigEvents    map[common.Hash][]abi.Event
+	knownMethods map[string]bool
+	knownEvents  map[string]bool
+}
+
+// NewDecoder returns a Decoder with the embedded ERC20, ERC721 and ERC1155 ABIs registered and
+// the offline signature database loaded.
+func NewDecoder() (*Decoder, error) {
+	d := &Decoder{
+		methods:      map[[4]byte][]abi.Method{},
+		events:       map[common.Hash][]abi.Event{},
+		sigMethods:   map[[4]byte][]abi.Method{},
+		sigEvents:    map[common.Hash][]abi.Event{},
+		knownMethods: map[string]bool{},
+		knownEvents:  map[string]bool{},
+	}
+	for _, ins := range []*abi.ABI{
+		erc20ABI,
+		erc20MintBurnABI,
+		erc721ABI,
+		erc721EnumerableABI,
+		erc721MetadataABI,
+		erc721BurnableABI,
+		erc721PausableABI,
+		erc721URIStorageABI,
+		erc721MintABI,
+		erc721MintWithURIABI,
+		erc721MintWithTokenIdAndURIABI,
+		erc1155ABI,
+		erc4626ABI,
+		ownableABI,
+		accessControlABI,
+		erc20VotesABI,
+	} {
+		d.RegisterABI(*ins)
+	}
+	for i, line := range strings.Split(signatureDatabase, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := d.RegisterSignature(line); err != nil {
+			return nil, fmt.Errorf("signature database line %d: %w", i+1, err)
+		}
+	}
+	return d, nil
+}
+
+// Register adds the methods and events of an ABI JSON.
+func (d *Decoder) Register(abiJSON string) error {
+	ins, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return err
+	}
+	d.RegisterABI(ins)
+	return nil
+}
+
+// RegisterABI adds the methods and events of a parsed ABI.
+func (d *Decoder) RegisterABI(ins abi.ABI) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, m := range ins.Methods {
+		d.methods[[4]byte(m.ID)] = append(d.methods[[4]byte(m.ID)], m)
+	}
+	for _, ev := range ins.Events {
+		d.events[ev.ID] = append(d.events[ev.ID], ev)
+	}
+}
+
+// RegisterSignature adds a human-readable declaration such as
+// "function transfer(address to, uint256 value)" or
+// "event Transfer(address indexed from, address indexed to, uint256 value)".
+// Parameter names are optional.
+func (d *Decoder) RegisterSignature(declaration string) error {
+	kind, rest, ok := strings.Cut(strings.TrimSpace(declaration), " ")
+	if !ok {
+		return fmt.Errorf("invalid declaration %q", declaration)
+	}
+	name, args, err := parseSignature(rest)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch kind {
+	case "function":
+		m := abi.NewMethod(name, name, abi.Function, "", false, false, args, nil)
+		key := m.Sig
+		if d.knownMethods[key] {
+			return nil
+		}
+		d.knownMethods[key] = true
+		d.sigMethods[[4]byte(m.ID)] = append(d.sigMethods[[4]byte(m.ID)], m)
+	case "event":
+		ev := abi.NewEvent(name, name, false, args)
+		key := ev.Sig + fmt.Sprint(len(indexedArguments(args)))
+		if d.knownEvents[key] {
+			return nil
+		}
+		d.knownEvents[key] = true
+		d.sigEvents[ev.ID] = append(d.sigEvents[ev.ID], ev)
+	default:
+		return fmt.Errorf("invalid declaration kind %q", kind)
+	}
+	return nil
+}
+
+// DecodeCall decodes transaction calldata, e.g. the Data() of a transaction from TransactionByHash.
+func (d *Decoder) DecodeCall(data []byte) (*DecodedCall, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("calldata too short: %d bytes", len(data))
+	}
+	selector := [4]byte(data[:4])
+	d.mu.RLock()
+	candidates := append(append([]abi.Method{}, d.methods[selector]...), d.sigMethods[selector]...)
+	d.mu.RUnlock()
+
+	var lastErr error
+	for _, m := range candidates {
+		values, err := m.Inputs.Unpack(data[4:])
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return &DecodedCall{
+			Name:      m.RawName,
+			Signature: m.Sig,
+			Selector:  selector,
+			Args:      decodedArgs(m.Inputs, values),
+			method:    m,
+		}, nil
+	}
+	if lastErr != nil {
+		return nil, fmt.Errorf("%s: %w", hexutil.Encode(selector[:]), lastErr)
+	}
+	return nil, fmt.Errorf("%w %s", ErrUnknownSelector, hexutil.Encode(selector[:]))
+}
+
+// DecodeTransaction decodes the calldata of tx.
+func (d *Decoder) DecodeTransaction(tx *types.Transaction) (*DecodedCall, error) {
+	return d.DecodeCall(tx.Data())
+}
+
+// DecodeReturn decodes the return data of the call made with calldata. Only methods of
+// registered ABIs know their outputs.
+func (d *Decoder) DecodeReturn(calldata, returnData []byte) ([]DecodedArg, error) {
+	call, err := d.DecodeCall(calldata)
+	if err != nil {
+		return nil, err
+	}
+	if call.method.Outputs == nil {
+		return nil, fmt.Errorf("no outputs known for %s", call.Signature)
+	}
+	values, err := call.method.Outputs.Unpack(returnData)
+	if err != nil {
+		return nil, err
+	}
+	return decodedArgs(call.method.Outputs, values), nil
+}
+
+// DecodeLog decodes an event log. Events sharing a topic, such as the ERC20 and ERC721
+// Transfer, are told apart by the number of indexed topics.
+func (d *Decoder) DecodeLog(log types.Log) (*DecodedLog, error) {
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("%w: anonymous log", ErrUnknownEvent)
+	}
+	d.mu.RLock()
+	candidates := append(append([]abi.Event{}, d.events[log.Topics[0]]...), d.sigEvents[log.Topics[0]]...)
+	d.mu.RUnlock()
+
+	var lastErr error
+	for _, ev := range candidates {
+		inputs := namedArguments(ev.Inputs)
+		indexed := indexedArguments(inputs)
+		if len(indexed) != len(log.Topics)-1 {
+			continue
+		}
+		data, err := ev.Inputs.NonIndexed().Unpack(log.Data)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		topics := map[string]interface{}{}
+		if err := abi.ParseTopicsIntoMap(topics, indexed, log.Topics[1:]); err != nil {
+			lastErr = err
+			continue
+		}
+		args := make([]DecodedArg, 0, len(inputs))
+		for _, arg := range inputs {
+			var value interface{}
+			if arg.Indexed {
+				value = topics[arg.Name]
+			} else {
+				value, data = data[0], data[1:]
+			}
+			args = append(args, DecodedArg{Name: arg.Name, Type: arg.Type.String(), Value: value})
+		}
+		return &DecodedLog{Name: ev.RawName, Signature: ev.Sig, Args: args}, nil
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, fmt.Errorf("%w %s", ErrUnknownEvent, log.Topics[0].Hex())
+}
+
+func decodedArgs(args abi.Arguments, values []interface{}) []DecodedArg {
+	out := make([]DecodedArg, len(values))
+	for i, v := range values {
+		out[i] = DecodedArg{Name: argumentName(args[i], i), Type: args[i].Type.String(), Value: v}
+	}
+	return out
+}
+
+func argumentName(arg abi.Argument, i int) string {
+	if arg.Name == "" {
+		return "arg" + strconv.Itoa(i)
+	}
+	return arg.Name
+}
+
+// namedArguments fills in the names ParseTopicsIntoMap keys the values by.
+func namedArguments(args abi.Arguments) abi.Arguments {
+	out := make(abi.Arguments, len(args))
+	for i, arg := range args {
+		out[i] = arg
+		out[i].Name = argumentName(arg, i)
+	}
+	return out
+}
+
+// parseSignature parses "name(type [indexed] [name], ...)" where types may be tuples "(...)".
+func parseSignature(s string) (string, abi.Arguments, error) {
+	s = strings.TrimSpace(s)
+	open := strings.Index(s, "(")
+	if open <= 0 || !strings.HasSuffix(s, ")") {
+		return "", nil, fmt.Errorf("invalid signature %q", s)
+	}
+	params, err := parseParams(s[open+1 : len(s)-1])
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", s, err)
+	}
+	args := make(abi.Arguments, 0, len(params))
+	for _, p := range params {
+		typ, err := abi.NewType(p.Type, "", p.Components)
+		if err != nil {
+			return "", nil, fmt.Errorf("%s: %w", s, err)
+		}
+		args = append(args, abi.Argument{Name: p.Name, Type: typ, Indexed: p.Indexed})
+	}
+	return s[:open], args, nil
+}
+
+func parseParams(s string) ([]abi.ArgumentMarshaling, error) {
+	var params []abi.ArgumentMarshaling
+	if strings.TrimSpace(s) == "" {
+		return params, nil
+	}
+	depth, start := 0, 0
+	var parts []string
+	for i, c := range s {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unbalanced parentheses")
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unbalanced parentheses")
+	}
+	parts = append(parts, s[start:])
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		var p abi.ArgumentMarshaling
+		rest := part
+		if strings.HasPrefix(part, "(") {
+			end := strings.LastIndex(part, ")")
+			components, err := parseParams(part[1:end])
+			if err != nil {
+				return nil, err
+			}
+			for i := range components {
+				if components[i].Name == "" {
+					components[i].Name = "field" + strconv.Itoa(i)
+				}
+			}
+			p.Components = components
+			rest = part[end+1:]
+			suffix, remaining, _ := strings.Cut(rest, " ")
+			p.Type = "tuple" + suffix
+			rest = remaining
+		} else {
+			p.Type, rest, _ = strings.Cut(part, " ")
+		}
+		for _, word := range strings.Fields(rest) {
+			if word == "indexed" {
+				p.Indexed = true
+			} else {
+				p.Name = word
+			}
+		}
+		if p.Type == "" {
+			return nil, fmt.Errorf("missing type in %q", part)
+		}
+		params = append(params, p)
+	}
+	return params, nil
+}
+
+func formatDecodedArgs(args []DecodedArg) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = arg.Name + ": " + FormatABIValue(arg.Value)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// FormatABIValue renders a value unpacked by go-ethereum: addresses and bytes as hex, integers in
+// decimal, strings quoted, arrays as [..] and tuples as {name: value}.
+func FormatABIValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case common.Address:
+		return x.Hex()
+	case common.Hash:
+		return x.Hex()
+	case *big.Int:
+		return x.String()
+	case []byte:
+		return hexutil.Encode(x)
+	case string:
+		return strconv.Quote(x)
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			bz := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(bz), rv)
+			return hexutil.Encode(bz)
+		}
+		fallthrough
+	case reflect.Slice:
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = FormatABIValue(rv.Index(i).Interface())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case reflect.Struct:
+		items := make([]string, rv.NumField())
+		for i := range items {
+			field := rv.Type().Field(i)
+			name := field.Tag.Get("json")
+			if name == "" {
+				name = field.Name
+			}
+			items[i] = name + ": " + FormatABIValue(rv.Field(i).Interface())
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,104 @@
+package ethcli
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func Test_DecoderCall(t *testing.T) {
+	d, err := NewDecoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := common.HexToAddress("0x5555555555555555555555555555555555555555")
+
+	data, _ := ERC20TransferData(other.Hex(), "1000")
+	call, err := d.DecodeCall(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if BytesToHex(call.Selector[:]) != "a9059cbb" || call.Signature != "transfer(address,uint256)" {
+		t.Fatalf("unexpected call %+v", call)
+	}
+	if call.String() != "transfer(_to: "+other.Hex()+", _value: 1000)" {
+		t.Fatalf("unexpected rendering %s", call)
+	}
+
+	// only known from the signature database
+	data, _ = SafeApproveHashData(common.HexToHash("0x01"))
+	if call, err = d.DecodeCall(data); err != nil || call.Name != "approveHash" || call.Args[0].Name != "hashToApprove" {
+		t.Fatalf("unexpected call %+v %v", call, err)
+	}
+	data, _ = Permit2PermitData(exampleAddress.Hex(), PermitSingle{
+		Details:     PermitDetails{Token: exampleToken, Amount: big.NewInt(1), Expiration: big.NewInt(2), Nonce: big.NewInt(3)},
+		Spender:     other,
+		SigDeadline: big.NewInt(4),
+	}, []byte{0xaa})
+	if call, err = d.DecodeCall(data); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(call.String(), "permitSingle: {details: {token: "+exampleToken.Hex()+", amount: 1, expiration: 2, nonce: 3}, spender: "+other.Hex()+", sigDeadline: 4}, signature: 0xaa") {
+		t.Fatalf("unexpected rendering %s", call)
+	}
+
+	if _, err := d.DecodeCall([]byte{1, 2, 3, 4}); !errors.Is(err, ErrUnknownSelector) {
+		t.Fatalf("expected unknown selector, got %v", err)
+	}
+	if err := d.RegisterSignature("function frob(uint256[2] pair, (bool ok, string note)[] notes)"); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.RegisterSignature("frob(uint256"); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
+
+func Test_DecoderReturnAndLog(t *testing.T) {
+	d, err := NewDecoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ins, _ := abi.JSON(strings.NewReader(openzeppelinERC20Abi))
+	calldata, _ := ins.Pack("balanceOf", exampleAddress)
+	out, err := d.DecodeReturn(calldata, common.LeftPadBytes(big.NewInt(7).Bytes(), 32))
+	if err != nil || len(out) != 1 || out[0].Value.(*big.Int).Int64() != 7 {
+		t.Fatalf("unexpected return %+v %v", out, err)
+	}
+
+	other := common.HexToAddress("0x5555555555555555555555555555555555555555")
+	log := types.Log{
+		Topics: []common.Hash{transferTopic, addressTopic(exampleAddress), addressTopic(other)},
+		Data:   common.LeftPadBytes(big.NewInt(42).Bytes(), 32),
+	}
+	decoded, err := d.DecodeLog(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.String() != "Transfer(from: "+exampleAddress.Hex()+", to: "+other.Hex()+", value: 42)" {
+		t.Fatalf("unexpected log %s", decoded)
+	}
+
+	log.Topics = append(log.Topics, common.BigToHash(big.NewInt(9)))
+	log.Data = nil
+	if decoded, err = d.DecodeLog(log); err != nil || decoded.Args[2].Name != "tokenId" || decoded.Args[2].Value.(*big.Int).Int64() != 9 {
+		t.Fatalf("unexpected ERC721 log %v %v", decoded, err)
+	}
+
+	// only known from the signature database
+	log = types.Log{Topics: []common.Hash{
+		crypto.Keccak256Hash([]byte("OwnershipTransferred(address,address)")), addressTopic(exampleAddress), addressTopic(other),
+	}}
+	if decoded, err = d.DecodeLog(log); err != nil || decoded.Args[1].Value != other {
+		t.Fatalf("unexpected log %v %v", decoded, err)
+	}
+
+	if _, err := d.DecodeLog(types.Log{Topics: []common.Hash{{}}}); !errors.Is(err, ErrUnknownEvent) {
+		t.Fatalf("expected unknown event, got %v", err)
+	}
+}
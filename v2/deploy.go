@@ -0,0 +1,193 @@
+package ethcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Create2Factory is the canonical deterministic deployment proxy, deployed at the same address on most chains.
+// Its calldata is salt(32 bytes) ++ init code.
+const Create2Factory = "0x4e59b44847b379578588920cA78FbF26c0B4956C"
+
+var (
+	// ErrDeployFailed is returned when the deployment transaction is mined but reverted.
+	ErrDeployFailed = errors.New("deployment failed")
+	// ErrNoCreate2Factory is returned when Create2Factory has no code on the connected chain.
+	ErrNoCreate2Factory = errors.New("create2 factory not deployed")
+	// ErrNotDeployed is returned when an address has no contract code.
+	ErrNotDeployed = errors.New("no contract code")
+)
+
+// DeployData returns the init code of a contract: bytecode followed by the ABI encoded constructor args.
+// abiJSON may be empty when the constructor takes no arguments; args are converted like Contract.Pack.
+func DeployData(bytecode string, abiJSON string, args ...interface{}) ([]byte, error) {
+	code := HexToBytes(strings.TrimSpace(bytecode))
+	if len(code) == 0 {
+		return nil, errors.New("empty bytecode")
+	}
+	if abiJSON == "" {
+		if len(args) > 0 {
+			return nil, errors.New("constructor args given without abi")
+		}
+		return code, nil
+	}
+
+	ins, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, err
+	}
+	converted, err := ConvertArguments(ins.Constructor.Inputs, args)
+	if err != nil {
+		return nil, fmt.Errorf("constructor: %w", err)
+	}
+	packed, err := ins.Pack("", converted...)
+	if err != nil {
+		return nil, err
+	}
+	return append(code, packed...), nil
+}
+
+// Deploy sends a contract creation transaction signed by key and waits for it to be mined.
+// It returns the address of the new contract and the receipt.
+func Deploy(ctx context.Context, cli *ethclient.Client, key string, bytecode string, abiJSON string, args ...interface{}) (string, *types.Receipt, error) {
+	data, err := DeployData(bytecode, abiJSON, args...)
+	if err != nil {
+		return "", nil, err
+	}
+
+	txHash, err := SendLegacyTx(ctx, cli, key, nil, "0", BytesToHex(data), "0", 0)
+	if err != nil {
+		return "", nil, err
+	}
+	receipt, err := waitMined(ctx, cli, common.HexToHash(txHash))
+	if err != nil {
+		return "", nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return "", receipt, fmt.Errorf("%w: tx %s", ErrDeployFailed, txHash)
+	}
+	return receipt.ContractAddress.Hex(), receipt, nil
+}
+
+// waitMined polls for the receipt of txHash until it is mined or ctx is done.
+func waitMined(ctx context.Context, cli *ethclient.Client, txHash common.Hash) (*types.Receipt, error) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		receipt, err := cli.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// CreateAddress predicts the address of a contract created by deployer with the given nonce.
+func CreateAddress(deployer string, nonce uint64) string {
+	return crypto.CreateAddress(common.HexToAddress(deployer), nonce).Hex()
+}
+
+// Create2Address predicts the address of a contract created with CREATE2 by deployer.
+func Create2Address(deployer string, salt [32]byte, initCodeHash common.Hash) string {
+	return crypto.CreateAddress2(common.HexToAddress(deployer), salt, initCodeHash.Bytes()).Hex()
+}
+
+// DeterministicDeployData returns the Create2Factory calldata deploying initCode with salt.
+func DeterministicDeployData(salt [32]byte, initCode []byte) []byte {
+	return append(salt[:], initCode...)
+}
+
+// DeterministicAddress predicts the address at which Create2Factory deploys initCode with salt.
+func DeterministicAddress(salt [32]byte, initCode []byte) string {
+	return Create2Address(Create2Factory, salt, crypto.Keccak256Hash(initCode))
+}
+
+// DeterministicDeploy deploys initCode through Create2Factory and waits for it to be mined.
+// The receipt is nil when the contract already exists at the predicted address.
+func DeterministicDeploy(ctx context.Context, cli *ethclient.Client, key string, salt [32]byte, initCode []byte) (string, *types.Receipt, error) {
+	address := DeterministicAddress(salt, initCode)
+	code, err := cli.CodeAt(ctx, common.HexToAddress(address), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(code) > 0 {
+		return address, nil, nil
+	}
+
+	factory, err := cli.CodeAt(ctx, common.HexToAddress(Create2Factory), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(factory) == 0 {
+		return "", nil, ErrNoCreate2Factory
+	}
+
+	to := Create2Factory
+	txHash, err := SendLegacyTx(ctx, cli, key, &to, "0", BytesToHex(DeterministicDeployData(salt, initCode)), "0", 0)
+	if err != nil {
+		return "", nil, err
+	}
+	receipt, err := waitMined(ctx, cli, common.HexToHash(txHash))
+	if err != nil {
+		return "", nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return "", receipt, fmt.Errorf("%w: tx %s", ErrDeployFailed, txHash)
+	}
+	return address, receipt, nil
+}
+
+// DeploymentBlock returns the block in which contract was deployed, found by a binary search over
+// eth_getCode up to blockNumber (nil for the head). It needs a node serving historical state and
+// assumes the code was never removed, e.g. by SELFDESTRUCT and a later redeployment.
+func DeploymentBlock(ctx context.Context, cli *ethclient.Client, contract string, blockNumber *big.Int) (*big.Int, error) {
+	address := common.HexToAddress(contract)
+	if blockNumber == nil {
+		head, err := cli.BlockNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		blockNumber = new(big.Int).SetUint64(head)
+	}
+	code, err := cli.CodeAt(ctx, address, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return nil, fmt.Errorf("%w at %s in block %s", ErrNotDeployed, address.Hex(), blockNumber)
+	}
+
+	// the code exists at hi and not before lo
+	lo, hi := uint64(0), blockNumber.Uint64()
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		code, err := cli.CodeAt(ctx, address, new(big.Int).SetUint64(mid))
+		if err != nil {
+			return nil, err
+		}
+		if len(code) > 0 {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return new(big.Int).SetUint64(hi), nil
+}
@@ -0,0 +1,174 @@
+package ethcli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const deployTestAbi = `[{"type":"constructor","inputs":[{"name":"supply","type":"uint256"},{"name":"name","type":"string"}],"stateMutability":"nonpayable"}]`
+
+func Test_CreateAddress(t *testing.T) {
+	deployer := "0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0"
+	if got := CreateAddress(deployer, 0); got != common.HexToAddress("0xcd234a471b72ba2f1ccf0a70fcaba648a5eecd8d").Hex() {
+		t.Fatalf("nonce 0: %s", got)
+	}
+	if got := CreateAddress(deployer, 1); got != common.HexToAddress("0x343c43a37d37dff08ae8c4a11544c718abb4fcf8").Hex() {
+		t.Fatalf("nonce 1: %s", got)
+	}
+}
+
+func Test_Create2Address(t *testing.T) {
+	// EIP-1014 examples
+	tests := []struct {
+		deployer string
+		salt     common.Hash
+		initCode string
+		want     string
+	}{
+		{"0x0000000000000000000000000000000000000000", common.Hash{}, "0x00", "0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38"},
+		{"0xdeadbeef00000000000000000000000000000000", common.Hash{}, "0x00", "0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3"},
+		{"0x00000000000000000000000000000000deadbeef", common.HexToHash("0xcafebabe"), "0xdeadbeef", "0x60f3f640a8508fC6a86d45DF051962668E1e8AC7"},
+	}
+	for _, tt := range tests {
+		initCode := HexToBytes(tt.initCode)
+		if got := Create2Address(tt.deployer, tt.salt, crypto.Keccak256Hash(initCode)); got != tt.want {
+			t.Errorf("Create2Address(%s) = %s, want %s", tt.deployer, got, tt.want)
+		}
+	}
+}
+
+func Test_DeployData(t *testing.T) {
+	data, err := DeployData("0x6080", deployTestAbi, "1000", "Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte{0x60, 0x80}) || len(data) != 2+32*4 {
+		t.Fatalf("init code %x", data)
+	}
+	if _, err := DeployData("0x6080", "", "1000"); err == nil {
+		t.Fatal("expected error for args without abi")
+	}
+}
+
+func Test_Deploy(t *testing.T) {
+	pool := newMockTxPool(func(tx *types.Transaction) *types.Receipt {
+		return &types.Receipt{
+			Status:          types.ReceiptStatusSuccessful,
+			ContractAddress: common.HexToAddress(CreateAddress(exampleAddress.Hex(), tx.Nonce())),
+			Logs:            []*types.Log{},
+		}
+	})
+	rpc := newMockRPC(t, pool.handlers())
+
+	address, receipt, err := Deploy(context.Background(), rpc.client(t), exampleKey, "0x6080", deployTestAbi, 1000, "Token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := CreateAddress(exampleAddress.Hex(), 0); address != want {
+		t.Fatalf("address %s, want %s", address, want)
+	}
+	txs := pool.transactions()
+	if len(txs) != 1 || txs[0].To() != nil || receipt.TxHash != txs[0].Hash() {
+		t.Fatalf("unexpected transactions %v", txs)
+	}
+}
+
+func Test_DeployReverted(t *testing.T) {
+	pool := newMockTxPool(func(tx *types.Transaction) *types.Receipt {
+		return &types.Receipt{Status: types.ReceiptStatusFailed, Logs: []*types.Log{}}
+	})
+	rpc := newMockRPC(t, pool.handlers())
+
+	_, receipt, err := Deploy(context.Background(), rpc.client(t), exampleKey, "0x6080", "")
+	if !errors.Is(err, ErrDeployFailed) || receipt == nil {
+		t.Fatalf("got %v, want ErrDeployFailed with receipt", err)
+	}
+}
+
+func Test_DeterministicDeploy(t *testing.T) {
+	salt := common.HexToHash("0x01")
+	initCode := []byte{0x60, 0x80, 0x60, 0x40}
+	predicted := common.HexToAddress(DeterministicAddress(salt, initCode))
+
+	pool := newMockTxPool(nil)
+	handlers := pool.handlers()
+	handlers["eth_getCode"] = func(params []json.RawMessage) (interface{}, *mockError) {
+		var address common.Address
+		_ = json.Unmarshal(params[0], &address)
+		switch {
+		case address == common.HexToAddress(Create2Factory):
+			return hexutil.Bytes{0x60}, nil
+		case address == predicted && len(pool.transactions()) > 0:
+			return hexutil.Bytes(initCode), nil
+		}
+		return hexutil.Bytes{}, nil
+	}
+	rpc := newMockRPC(t, handlers)
+	cli := rpc.client(t)
+
+	address, receipt, err := DeterministicDeploy(context.Background(), cli, exampleKey, salt, initCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if address != predicted.Hex() || receipt == nil {
+		t.Fatalf("address %s receipt %v", address, receipt)
+	}
+	txs := pool.transactions()
+	if len(txs) != 1 || *txs[0].To() != common.HexToAddress(Create2Factory) || !bytes.Equal(txs[0].Data(), DeterministicDeployData(salt, initCode)) {
+		t.Fatalf("unexpected factory call %v", txs)
+	}
+
+	// already deployed: no transaction is sent
+	address, receipt, err = DeterministicDeploy(context.Background(), cli, exampleKey, salt, initCode)
+	if err != nil || address != predicted.Hex() || receipt != nil {
+		t.Fatalf("redeploy: %s %v %v", address, receipt, err)
+	}
+	if n := len(pool.transactions()); n != 1 {
+		t.Fatalf("%d transactions sent, want 1", n)
+	}
+}
+
+// deployedAt serves eth_getCode for a contract deployed in block number.
+func deployedAt(number uint64) mockHandler {
+	return func(params []json.RawMessage) (interface{}, *mockError) {
+		var tag string
+		_ = json.Unmarshal(params[1], &tag)
+		if n, err := hexutil.DecodeUint64(tag); err == nil && n < number {
+			return hexutil.Bytes{}, nil
+		}
+		return hexutil.Bytes{0x60, 0x80}, nil
+	}
+}
+
+func Test_DeploymentBlock(t *testing.T) {
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_blockNumber": func(params []json.RawMessage) (interface{}, *mockError) {
+			return "0x1000000", nil
+		},
+		"eth_getCode": deployedAt(1234567),
+	})
+	cli := mock.client(t)
+
+	block, err := DeploymentBlock(context.Background(), cli, exampleToken.Hex(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Uint64() != 1234567 {
+		t.Fatalf("got block %s", block)
+	}
+	if calls := mock.count("eth_getCode"); calls > 26 {
+		t.Fatalf("%d eth_getCode calls", calls)
+	}
+	if _, err := DeploymentBlock(context.Background(), cli, exampleToken.Hex(), big.NewInt(1000)); !errors.Is(err, ErrNotDeployed) {
+		t.Fatalf("got %v, want ErrNotDeployed", err)
+	}
+}
@@ -0,0 +1,225 @@
+package ethcli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+var (
+	eip1271Abi = `[{"inputs":[{"internalType":"bytes32","name":"hash","type":"bytes32"},{"internalType":"bytes","name":"signature","type":"bytes"}],"name":"isValidSignature","outputs":[{"internalType":"bytes4","name":"magicValue","type":"bytes4"}],"stateMutability":"view","type":"function"}]`
+
+	// EIP1271MagicValue is bytes4(keccak256("isValidSignature(bytes32,bytes)")).
+	EIP1271MagicValue = [4]byte{0x16, 0x26, 0xba, 0x7e}
+	// ERC6492MagicSuffix terminates signatures wrapped for not-yet-deployed contract wallets.
+	ERC6492MagicSuffix = common.FromHex("0x6492649264926492649264926492649264926492649264926492649264926492")
+)
+
+// ERC6492Signature is the payload of an ERC-6492 wrapped signature:
+// abi.encode(factory, factoryCalldata, signature) ‖ ERC6492MagicSuffix.
+type ERC6492Signature struct {
+	Factory         common.Address
+	FactoryCalldata []byte
+	Signature       []byte
+}
+
+// IsERC6492Signature reports whether signature carries the ERC-6492 magic suffix.
+func IsERC6492Signature(signature []byte) bool {
+	return len(signature) >= 32 && bytes.HasSuffix(signature, ERC6492MagicSuffix)
+}
+
+// ParseERC6492Signature unwraps an ERC-6492 signature.
+func ParseERC6492Signature(signature []byte) (*ERC6492Signature, error) {
+	if !IsERC6492Signature(signature) {
+		return nil, errors.New("not an ERC-6492 signature")
+	}
+	args, err := erc6492Arguments()
+	if err != nil {
+		return nil, err
+	}
+	values, err := args.Unpack(signature[:len(signature)-len(ERC6492MagicSuffix)])
+	if err != nil {
+		return nil, err
+	}
+	return &ERC6492Signature{
+		Factory:         values[0].(common.Address),
+		FactoryCalldata: values[1].([]byte),
+		Signature:       values[2].([]byte),
+	}, nil
+}
+
+// WrapERC6492Signature produces the ERC-6492 form of signature for a wallet that factory
+// deploys when called with factoryCalldata.
+func WrapERC6492Signature(factory string, factoryCalldata, signature []byte) ([]byte, error) {
+	args, err := erc6492Arguments()
+	if err != nil {
+		return nil, err
+	}
+	bz, err := args.Pack(common.HexToAddress(factory), factoryCalldata, signature)
+	if err != nil {
+		return nil, err
+	}
+	return append(bz, ERC6492MagicSuffix...), nil
+}
+
+func EIP1271IsValidSignatureData(hash common.Hash, signature []byte) ([]byte, error) {
+	return eip1271ABI.Pack("isValidSignature", hash, signature)
+}
+
+// EIP1271IsValidSignature calls isValidSignature(hash, signature) on a contract wallet and reports
+// whether it returned the magic value. A reverting call is reported as invalid rather than as an error.
+func EIP1271IsValidSignature(ctx context.Context, cli *ethclient.Client, wallet string, hash common.Hash, signature []byte, blockNumber *big.Int) (bool, error) {
+	data, err := EIP1271IsValidSignatureData(hash, signature)
+	if err != nil {
+		return false, err
+	}
+
+	contract := common.HexToAddress(wallet)
+	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
+		To:   &contract,
+		Data: data,
+	}, blockNumber)
+	if err != nil {
+		if isExecutionReverted(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return isEIP1271MagicValue(bz), nil
+}
+
+// VerifySignature checks that signer signed hash, whatever kind of account signer is:
+//   - ERC-6492 wrapped signatures are checked with a deployless eth_call that runs the factory
+//     call and then isValidSignature, so undeployed wallets verify without touching the chain.
+//     For a deployed wallet the factory call only runs when isValidSignature rejects the
+//     unwrapped signature, as ERC-6492 requires for wallets that need a prepare step;
+//   - accounts with code are checked with EIP-1271 isValidSignature;
+//   - other accounts are treated as EOAs and checked by ecrecover.
+func VerifySignature(ctx context.Context, cli *ethclient.Client, signer string, hash common.Hash, signature []byte, blockNumber *big.Int) (bool, error) {
+	account := common.HexToAddress(signer)
+	code, err := cli.CodeAt(ctx, account, blockNumber)
+	if err != nil {
+		return false, err
+	}
+
+	if IsERC6492Signature(signature) {
+		wrapped, err := ParseERC6492Signature(signature)
+		if err != nil {
+			return false, err
+		}
+		if len(code) > 0 {
+			ok, err := EIP1271IsValidSignature(ctx, cli, signer, hash, wrapped.Signature, blockNumber)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return verifyERC6492Signature(ctx, cli, account, hash, wrapped, blockNumber)
+	}
+
+	if len(code) > 0 {
+		return EIP1271IsValidSignature(ctx, cli, signer, hash, signature, blockNumber)
+	}
+
+	recovered, err := RecoverAddress(hash.Bytes(), signature)
+	if err != nil {
+		return false, nil
+	}
+	return recovered == account, nil
+}
+
+// VerifyPersonalMessage is VerifySignature for an EIP-191 personal_sign message.
+func VerifyPersonalMessage(ctx context.Context, cli *ethclient.Client, signer string, message []byte, signature []byte, blockNumber *big.Int) (bool, error) {
+	return VerifySignature(ctx, cli, signer, PersonalMessageHash(message), signature, blockNumber)
+}
+
+// VerifyTypedDataSignature is VerifySignature for EIP-712 typed data.
+func VerifyTypedDataSignature(ctx context.Context, cli *ethclient.Client, signer string, typedData apitypes.TypedData, signature []byte, blockNumber *big.Int) (bool, error) {
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return false, err
+	}
+	return VerifySignature(ctx, cli, signer, hash, signature, blockNumber)
+}
+
+// erc6492ValidatorCode is deployless creation code that validates an ERC-6492 signature inside a
+// single eth_call. The creation data that follows the code is four words (factory, wallet,
+// len(factoryCalldata), len(isValidSignature calldata)) and then both calldata blobs. The code
+// calls factory with factoryCalldata, calls isValidSignature on wallet and returns one word:
+// 1 for the magic value, 0 otherwise, 2 when the factory call reverted.
+var erc6492ValidatorCode = common.FromHex("0x6100603803610060600039600051156100285760006000604051608060006000515af115610055575b6000600052602060006060516040516080016020515afa60005160e01c631626ba7e141660005260206000f35b600260005260206000f3")
+
+// verifyERC6492Signature runs the factory call followed by isValidSignature through a deployless
+// eth_call, which every node supports and which writes nothing on chain.
+func verifyERC6492Signature(ctx context.Context, cli *ethclient.Client, wallet common.Address, hash common.Hash, wrapped *ERC6492Signature, blockNumber *big.Int) (bool, error) {
+	check, err := EIP1271IsValidSignatureData(hash, wrapped.Signature)
+	if err != nil {
+		return false, err
+	}
+
+	data := append([]byte{}, erc6492ValidatorCode...)
+	data = append(data, common.LeftPadBytes(wrapped.Factory.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(wallet.Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(len(wrapped.FactoryCalldata))).Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(len(check))).Bytes(), 32)...)
+	data = append(data, wrapped.FactoryCalldata...)
+	data = append(data, check...)
+
+	bz, err := cli.CallContract(ctx, ethereum.CallMsg{Data: data}, blockNumber)
+	if err != nil {
+		return false, err
+	}
+	if len(bz) != 32 {
+		return false, fmt.Errorf("%w: ERC-6492 validator returned %d bytes", ErrUnexpectedResult, len(bz))
+	}
+	switch new(big.Int).SetBytes(bz).Uint64() {
+	case 1:
+		return true, nil
+	case 2:
+		return false, errors.New("ERC-6492 factory call failed")
+	default:
+		return false, nil
+	}
+}
+
+func erc6492Arguments() (abi.Arguments, error) {
+	addressTy, err := abi.NewType("address", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	bytesTy, err := abi.NewType("bytes", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	return abi.Arguments{{Type: addressTy}, {Type: bytesTy}, {Type: bytesTy}}, nil
+}
+
+func isEIP1271MagicValue(bz []byte) bool {
+	return len(bz) >= 4 && bytes.Equal(bz[:4], EIP1271MagicValue[:])
+}
+
+// isExecutionReverted reports whether err is a JSON-RPC revert as opposed to a transport failure.
+func isExecutionReverted(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == 3 {
+		return true
+	}
+	return strings.Contains(err.Error(), "execution reverted")
+}
+
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	return hexutil.EncodeBig(number)
+}
@@ -0,0 +1,123 @@
+package ethcli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func Test_VerifySignature(t *testing.T) {
+	wallet := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	factory := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	hash := PersonalMessageHash([]byte("hello"))
+	signature, err := PersonalSign(exampleKey, []byte("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	magic := hexutil.Encode(common.RightPadBytes(EIP1271MagicValue[:], 32))
+
+	var (
+		deployed  bool
+		walletOK  = true
+		validator = "0x01"
+	)
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_getCode": func(params []json.RawMessage) (interface{}, *mockError) {
+			var addr common.Address
+			_ = json.Unmarshal(params[0], &addr)
+			if addr == wallet && deployed {
+				return "0x6000", nil
+			}
+			return "0x", nil
+		},
+		"eth_call": func(params []json.RawMessage) (interface{}, *mockError) {
+			var msg struct {
+				To    *common.Address `json:"to"`
+				Input hexutil.Bytes   `json:"input"`
+			}
+			_ = json.Unmarshal(params[0], &msg)
+			if msg.To != nil {
+				if !walletOK {
+					return hexutil.Encode(make([]byte, 32)), nil
+				}
+				return magic, nil
+			}
+			if !bytes.HasPrefix(msg.Input, erc6492ValidatorCode) {
+				t.Fatalf("unexpected deployless call %x", msg.Input)
+			}
+			words := msg.Input[len(erc6492ValidatorCode):]
+			if common.BytesToAddress(words[:32]) != factory || common.BytesToAddress(words[32:64]) != wallet {
+				t.Fatalf("unexpected validator arguments %x", words[:64])
+			}
+			if !bytes.Equal(words[128:130], []byte{0xde, 0xad}) {
+				t.Fatalf("unexpected factory calldata %x", words[128:130])
+			}
+			return hexutil.Encode(common.LeftPadBytes(hexutil.MustDecode(validator), 32)), nil
+		},
+	})
+	cli := mock.client(t)
+	ctx := context.Background()
+
+	ok, err := VerifySignature(ctx, cli, exampleAddress.Hex(), hash, signature, nil)
+	if err != nil || !ok {
+		t.Fatalf("EOA signature: %v %v", ok, err)
+	}
+	ok, err = VerifySignature(ctx, cli, wallet.Hex(), hash, signature, nil)
+	if err != nil || ok {
+		t.Fatalf("EOA signature for other address: %v %v", ok, err)
+	}
+
+	deployed = true
+	ok, err = VerifyPersonalMessage(ctx, cli, wallet.Hex(), []byte("hello"), signature, nil)
+	if err != nil || !ok {
+		t.Fatalf("EIP-1271 signature: %v %v", ok, err)
+	}
+	if mock.count("eth_call") != 1 {
+		t.Fatalf("expected one isValidSignature call, got %d", mock.count("eth_call"))
+	}
+
+	deployed = false
+	wrapped, err := WrapERC6492Signature(factory.Hex(), []byte{0xde, 0xad}, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseERC6492Signature(wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Factory != factory || string(parsed.Signature) != string(signature) {
+		t.Fatalf("unexpected unwrap %+v", parsed)
+	}
+	ok, err = VerifySignature(ctx, cli, wallet.Hex(), hash, wrapped, nil)
+	if err != nil || !ok {
+		t.Fatalf("ERC-6492 signature: %v %v", ok, err)
+	}
+	if mock.count("eth_call") != 2 {
+		t.Fatalf("expected one deployless validation, got %d calls", mock.count("eth_call"))
+	}
+
+	validator = "0x02"
+	if _, err = VerifySignature(ctx, cli, wallet.Hex(), hash, wrapped, nil); err == nil {
+		t.Fatal("expected failed factory call to be reported")
+	}
+	validator = "0x00"
+	ok, err = VerifySignature(ctx, cli, wallet.Hex(), hash, wrapped, nil)
+	if err != nil || ok {
+		t.Fatalf("rejected ERC-6492 signature: %v %v", ok, err)
+	}
+
+	// A deployed wallet that rejects the unwrapped signature is re-checked after the prepare call.
+	deployed, walletOK, validator = true, false, "0x01"
+	calls := mock.count("eth_call")
+	ok, err = VerifySignature(ctx, cli, wallet.Hex(), hash, wrapped, nil)
+	if err != nil || !ok {
+		t.Fatalf("ERC-6492 signature after prepare: %v %v", ok, err)
+	}
+	if mock.count("eth_call")-calls != 2 {
+		t.Fatalf("expected isValidSignature then deployless validation, got %d calls", mock.count("eth_call")-calls)
+	}
+}
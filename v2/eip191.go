@@ -0,0 +1,108 @@
+package ethcli
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrHighS is returned for signatures whose s value is above secp256k1n/2. Such signatures are
+// malleable, have no EIP-2098 compact form and are rejected by OpenZeppelin's ECDSA.recover.
+var ErrHighS = errors.New("signature s value above secp256k1n/2")
+
+// PersonalMessageHash returns keccak256("\x19Ethereum Signed Message:\n" ‖ len(message) ‖ message).
+func PersonalMessageHash(message []byte) common.Hash {
+	return common.BytesToHash(accounts.TextHash(message))
+}
+
+// PersonalSign signs message as personal_sign does, returning a 65-byte signature with v in {27, 28}.
+func PersonalSign(key string, message []byte) ([]byte, error) {
+	priKey, err := crypto.HexToECDSA(key)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := crypto.Sign(PersonalMessageHash(message).Bytes(), priKey)
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+// RecoverPersonalSigner returns the address that personal_sign'ed message.
+func RecoverPersonalSigner(message []byte, signature []byte) (common.Address, error) {
+	return RecoverAddress(PersonalMessageHash(message).Bytes(), signature)
+}
+
+// VerifyPersonalSignature reports whether signature over message was produced by expected.
+func VerifyPersonalSignature(message []byte, signature []byte, expected string) (bool, error) {
+	signer, err := RecoverPersonalSigner(message, signature)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(signer.Hex(), common.HexToAddress(expected).Hex()), nil
+}
+
+// RecoverAddress recovers the signing address of hash. It accepts 65-byte [R || S || V]
+// signatures with V as 0/1 or 27/28, and 64-byte EIP-2098 compact [R || yParity+S] signatures.
+// Signatures with a high s value are rejected with ErrHighS.
+func RecoverAddress(hash []byte, signature []byte) (common.Address, error) {
+	sig, err := normalizeSignature(signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	pub, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// CompactSignature converts a 65-byte signature into its EIP-2098 64-byte form. EIP-2098 is only
+// defined for low s values, so high-s signatures are rejected with ErrHighS.
+func CompactSignature(signature []byte) ([]byte, error) {
+	if len(signature) != crypto.SignatureLength {
+		return nil, errors.New("invalid signature length")
+	}
+	sig, err := normalizeSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+	compact := sig[:64]
+	compact[32] |= sig[crypto.RecoveryIDOffset] << 7
+	return compact, nil
+}
+
+// normalizeSignature returns a copy of signature in the 65-byte form with V in {0, 1}
+// expected by crypto.SigToPub, rejecting high s values.
+func normalizeSignature(signature []byte) ([]byte, error) {
+	sig := make([]byte, crypto.SignatureLength)
+	switch len(signature) {
+	case crypto.SignatureLength:
+		copy(sig, signature)
+		v := sig[crypto.RecoveryIDOffset]
+		if v >= 27 {
+			v -= 27
+		}
+		if v > 1 {
+			return nil, errors.New("invalid signature recovery id")
+		}
+		sig[crypto.RecoveryIDOffset] = v
+	case 64:
+		copy(sig, signature)
+		sig[crypto.RecoveryIDOffset] = sig[32] >> 7
+		sig[32] &= 0x7f
+	default:
+		return nil, errors.New("invalid signature length")
+	}
+	if new(big.Int).SetBytes(sig[32:64]).Cmp(secp256k1HalfN) > 0 {
+		return nil, ErrHighS
+	}
+	return sig, nil
+}
+
+var secp256k1HalfN = new(big.Int).Rsh(crypto.S256().Params().N, 1)
@@ -0,0 +1,50 @@
+package ethcli
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func Test_PersonalSign(t *testing.T) {
+	message := []byte("hello")
+	signature, err := PersonalSign(exampleKey, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	legacy := append([]byte{}, signature...)
+	legacy[64] -= 27
+	compact, err := CompactSignature(signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sig := range [][]byte{signature, legacy, compact} {
+		ok, err := VerifyPersonalSignature(message, sig, exampleAddress.Hex())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatalf("signature %x does not verify", sig)
+		}
+	}
+
+	if _, err := RecoverPersonalSigner(message, signature[:63]); err == nil {
+		t.Fatal("expected error for short signature")
+	}
+
+	// the same signature with s' = n - s and the other recovery id
+	highS := append([]byte{}, signature...)
+	s := new(big.Int).Sub(crypto.S256().Params().N, new(big.Int).SetBytes(signature[32:64]))
+	copy(highS[32:64], common.LeftPadBytes(s.Bytes(), 32))
+	highS[64] = 27 + 28 - highS[64]
+	if _, err := RecoverPersonalSigner(message, highS); !errors.Is(err, ErrHighS) {
+		t.Fatalf("got %v, want ErrHighS", err)
+	}
+	if _, err := CompactSignature(highS); !errors.Is(err, ErrHighS) {
+		t.Fatalf("got %v, want ErrHighS", err)
+	}
+}
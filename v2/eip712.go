@@ -0,0 +1,93 @@
+package ethcli
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// ParseTypedData decodes an eth_signTypedData_v4 JSON payload.
+// Integers above 2^53 must be given as decimal or 0x-prefixed strings.
+func ParseTypedData(jsonData []byte) (apitypes.TypedData, error) {
+	var typedData apitypes.TypedData
+	if err := json.Unmarshal(jsonData, &typedData); err != nil {
+		return apitypes.TypedData{}, err
+	}
+	return typedData, nil
+}
+
+// DomainSeparator returns hashStruct(EIP712Domain) of the typed data.
+func DomainSeparator(typedData apitypes.TypedData) (common.Hash, error) {
+	bz, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(bz), nil
+}
+
+// StructHash returns hashStruct(primaryType, data) using the types declared in typedData.
+func StructHash(typedData apitypes.TypedData, primaryType string, data map[string]interface{}) (common.Hash, error) {
+	bz, err := typedData.HashStruct(primaryType, data)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(bz), nil
+}
+
+// TypedDataHash returns keccak256("\x19\x01" ‖ domainSeparator ‖ hashStruct(message)), the digest that gets signed.
+func TypedDataHash(typedData apitypes.TypedData) (common.Hash, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(hash), nil
+}
+
+// SignTypedData signs the EIP-712 digest of typedData, returning a 65-byte signature with v in {27, 28}
+// as produced by eth_signTypedData_v4.
+func SignTypedData(key string, typedData apitypes.TypedData) ([]byte, error) {
+	priKey, err := crypto.HexToECDSA(key)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := crypto.Sign(hash.Bytes(), priKey)
+	if err != nil {
+		return nil, err
+	}
+	signature[crypto.RecoveryIDOffset] += 27
+	return signature, nil
+}
+
+// SignTypedDataJSON is SignTypedData for an eth_signTypedData_v4 JSON payload.
+func SignTypedDataJSON(key string, jsonData []byte) ([]byte, error) {
+	typedData, err := ParseTypedData(jsonData)
+	if err != nil {
+		return nil, err
+	}
+	return SignTypedData(key, typedData)
+}
+
+// RecoverTypedDataSigner returns the address that produced signature over typedData.
+func RecoverTypedDataSigner(typedData apitypes.TypedData, signature []byte) (common.Address, error) {
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return RecoverAddress(hash.Bytes(), signature)
+}
+
+// VerifyTypedData reports whether signature over typedData was produced by expected.
+func VerifyTypedData(typedData apitypes.TypedData, signature []byte, expected string) (bool, error) {
+	signer, err := RecoverTypedDataSigner(typedData, signature)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(signer.Hex(), common.HexToAddress(expected).Hex()), nil
+}
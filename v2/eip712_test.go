@@ -0,0 +1,94 @@
+package ethcli
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// exampleMailTypedData is the Mail example from the EIP-712 specification.
+const exampleMailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func Test_TypedDataHash(t *testing.T) {
+	typedData, err := ParseTypedData([]byte(exampleMailTypedData))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	domainSeparator, err := DomainSeparator(typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if domainSeparator != common.HexToHash("0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f") {
+		t.Fatalf("domain separator %s", domainSeparator.Hex())
+	}
+
+	structHash, err := StructHash(typedData, typedData.PrimaryType, typedData.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if structHash != common.HexToHash("0xc52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e") {
+		t.Fatalf("struct hash %s", structHash.Hex())
+	}
+
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != common.HexToHash("0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2") {
+		t.Fatalf("digest %s", hash.Hex())
+	}
+}
+
+func Test_SignTypedDataJSON(t *testing.T) {
+	key := common.Bytes2Hex(crypto.Keccak256([]byte("cow")))
+	signature, err := SignTypedDataJSON(key, []byte(exampleMailTypedData))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "4355c47d63924e8a72e509b65029052eb6c299d53a04e167c5775fd466751c9d" +
+		"07299936d304c153f6443dfa05f40ff007d72911b6f72307f996231605b91562" + "1c"
+	if BytesToHex(signature) != want {
+		t.Fatalf("signature %x", signature)
+	}
+
+	typedData, _ := ParseTypedData([]byte(exampleMailTypedData))
+	ok, err := VerifyTypedData(typedData, signature, "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("signature does not verify")
+	}
+}
@@ -3,10 +3,7 @@ package ethcli
 import (
 	"context"
 	"math/big"
-	"strings"
 
-	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
@@ -16,244 +13,81 @@ var (
 )
 
 func ERC1155BalanceOf(ctx context.Context, cli *ethclient.Client, token string, owner string, tokenId *big.Int, blockNumber *big.Int) (*big.Int, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return nil, err
-	}
-	data, _ := ins.Pack("balanceOf", common.HexToAddress(owner), tokenId)
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return nil, err
-	}
-
-	results, err := ins.Unpack("balanceOf", bz)
-	if err != nil {
-		return nil, err
-	}
-
-	return results[0].(*big.Int), nil
+	return callResult[*big.Int](ctx, cli, erc1155ABI, token, "balanceOf", blockNumber, common.HexToAddress(owner), tokenId)
 }
 
 func ERC1155BalanceOfBatch(ctx context.Context, cli *ethclient.Client, token string, owners []string, tokenIds []*big.Int, blockNumber *big.Int) ([]*big.Int, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return nil, err
-	}
 	var accounts []common.Address
 	for _, v := range owners {
 		accounts = append(accounts, common.HexToAddress(v))
 	}
-	data, _ := ins.Pack("balanceOfBatch", accounts, tokenIds)
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return nil, err
-	}
-
-	results, err := ins.Unpack("balanceOfBatch", bz)
-	if err != nil {
-		return nil, err
-	}
-
-	return results[0].([]*big.Int), nil
+	return callResult[[]*big.Int](ctx, cli, erc1155ABI, token, "balanceOfBatch", blockNumber, accounts, tokenIds)
 }
 
 func ERC1155IsApprovedForAll(ctx context.Context, cli *ethclient.Client, token string, owner string, operator string, blockNumber *big.Int) (bool, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return false, err
-	}
-	data, _ := ins.Pack("isApprovedForAll", common.HexToAddress(owner), common.HexToAddress(operator))
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return false, err
-	}
-
-	results, err := ins.Unpack("isApprovedForAll", bz)
-	if err != nil {
-		return false, err
-	}
-
-	return results[0].(bool), nil
+	return callResult[bool](ctx, cli, erc1155ABI, token, "isApprovedForAll", blockNumber, common.HexToAddress(owner), common.HexToAddress(operator))
 }
 
 func ERC1155SafeBatchTransferFrom(ctx context.Context, cli *ethclient.Client, key string, token string, owner string, to string, ids []*big.Int, amounts []*big.Int, data []byte) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return "", err
-	}
-	bz, _ := ins.Pack("safeBatchTransferFrom", common.HexToAddress(owner), common.HexToAddress(to), ids, amounts, data)
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(bz), "0", 0)
+	return sendContract(ctx, cli, key, erc1155ABI, token, "safeBatchTransferFrom", common.HexToAddress(owner), common.HexToAddress(to), ids, amounts, data)
 }
 
 func ERC1155SafeTransferFrom(ctx context.Context, cli *ethclient.Client, key string, token string, owner string, to string, id *big.Int, amount *big.Int, data []byte) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return "", err
-	}
-	bz, _ := ins.Pack("safeTransferFrom", common.HexToAddress(owner), common.HexToAddress(to), id, amount, data)
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(bz), "0", 0)
+	return sendContract(ctx, cli, key, erc1155ABI, token, "safeTransferFrom", common.HexToAddress(owner), common.HexToAddress(to), id, amount, data)
 }
 
 func ERC1155SetApprovalForAll(ctx context.Context, cli *ethclient.Client, key string, token string, operator string, approved bool) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return "", err
-	}
-	bz, _ := ins.Pack("setApprovalForAll", common.HexToAddress(operator), approved)
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(bz), "0", 0)
+	return sendContract(ctx, cli, key, erc1155ABI, token, "setApprovalForAll", common.HexToAddress(operator), approved)
 }
 
 func ERC1155SupportsInterface(ctx context.Context, cli *ethclient.Client, token string, interfaceId [4]byte, blockNumber *big.Int) (bool, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return false, err
-	}
-	data, _ := ins.Pack("supportsInterface", interfaceId)
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return false, err
-	}
-
-	results, err := ins.Unpack("supportsInterface", bz)
-	if err != nil {
-		return false, err
-	}
-
-	return results[0].(bool), nil
+	return callResult[bool](ctx, cli, erc1155ABI, token, "supportsInterface", blockNumber, interfaceId)
 }
 
 func ERC1155Uri(ctx context.Context, cli *ethclient.Client, token string, tokenId *big.Int, blockNumber *big.Int) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("uri", tokenId)
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	results, err := ins.Unpack("uri", bz)
-	if err != nil {
-		return "", err
-	}
-
-	return results[0].(string), nil
+	return callResult[string](ctx, cli, erc1155ABI, token, "uri", blockNumber, tokenId)
 }
 
 func ERC1155Mint(ctx context.Context, cli *ethclient.Client, key string, token string, to string, id *big.Int, amount *big.Int, data []byte) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return "", err
-	}
-	bz, _ := ins.Pack("mint", common.HexToAddress(to), id, amount, data)
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(bz), "0", 0)
+	return sendContract(ctx, cli, key, erc1155ABI, token, "mint", common.HexToAddress(to), id, amount, data)
 }
 
 func ERC1155MintBatch(ctx context.Context, cli *ethclient.Client, key string, token string, to string, ids []*big.Int, amounts []*big.Int, data []byte) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return "", err
-	}
-	bz, _ := ins.Pack("mintBatch", common.HexToAddress(to), ids, amounts, data)
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(bz), "0", 0)
+	return sendContract(ctx, cli, key, erc1155ABI, token, "mintBatch", common.HexToAddress(to), ids, amounts, data)
 }
 
 func ERC1155Burn(ctx context.Context, cli *ethclient.Client, key string, token string, to string, id *big.Int, amount *big.Int) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return "", err
-	}
-	bz, _ := ins.Pack("burn", common.HexToAddress(to), id, amount)
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(bz), "0", 0)
+	return sendContract(ctx, cli, key, erc1155ABI, token, "burn", common.HexToAddress(to), id, amount)
 }
 
 func ERC1155BurnBatch(ctx context.Context, cli *ethclient.Client, key string, token string, to string, ids []*big.Int, amounts []*big.Int) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return "", err
-	}
-	bz, _ := ins.Pack("burnBatch", common.HexToAddress(to), ids, amounts)
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(bz), "0", 0)
+	return sendContract(ctx, cli, key, erc1155ABI, token, "burnBatch", common.HexToAddress(to), ids, amounts)
 }
 
 func ERC1155SafeBatchTransferFromData(from, to string, ids []*big.Int, amounts []*big.Int, data []byte) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("safeBatchTransferFrom", common.HexToAddress(from), common.HexToAddress(to), ids, amounts, data)
+	return erc1155ABI.Pack("safeBatchTransferFrom", common.HexToAddress(from), common.HexToAddress(to), ids, amounts, data)
 }
 
 func ERC1155SafeTransferFromData(from, to string, id *big.Int, amount *big.Int, data []byte) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("safeTransferFrom", common.HexToAddress(from), common.HexToAddress(to), id, amount, data)
+	return erc1155ABI.Pack("safeTransferFrom", common.HexToAddress(from), common.HexToAddress(to), id, amount, data)
 }
 
 func ERC1155SetApprovalForAllData(operator string, approved bool) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("setApprovalForAll", common.HexToAddress(operator), approved)
+	return erc1155ABI.Pack("setApprovalForAll", common.HexToAddress(operator), approved)
 }
 
 func ERC1155MintData(to string, id *big.Int, amount *big.Int, data []byte) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("mint", common.HexToAddress(to), id, amount, data)
+	return erc1155ABI.Pack("mint", common.HexToAddress(to), id, amount, data)
 }
 
 func ERC1155MintBatchData(to string, ids []*big.Int, amounts []*big.Int, data []byte) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("mintBatch", common.HexToAddress(to), ids, amounts, data)
+	return erc1155ABI.Pack("mintBatch", common.HexToAddress(to), ids, amounts, data)
 }
 
 func ERC1155BurnData(from string, id *big.Int, amount *big.Int) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("burn", common.HexToAddress(from), id, amount)
+	return erc1155ABI.Pack("burn", common.HexToAddress(from), id, amount)
 }
 
 func ERC1155BurnBatchData(from string, ids []*big.Int, amounts []*big.Int) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("burnBatch", common.HexToAddress(from), ids, amounts)
+	return erc1155ABI.Pack("burnBatch", common.HexToAddress(from), ids, amounts)
 }
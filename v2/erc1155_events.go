@@ -0,0 +1,247 @@
+package ethcli
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ERC1155TransferEvent is a decoded TransferSingle or TransferBatch log. A TransferSingle
+// carries one id; flattened TransferBatch logs yield one event per id with the same LogIndex.
+type ERC1155TransferEvent struct {
+	Token       common.Address
+	Operator    common.Address
+	From        common.Address
+	To          common.Address
+	Ids         []*big.Int
+	Values      []*big.Int
+	Batch       bool
+	Kind        TransferKind
+	BlockNumber uint64
+	TxHash      common.Hash
+	LogIndex    uint
+	Removed     bool
+}
+
+// ERC1155ApprovalForAllEvent is a decoded ApprovalForAll(account, operator, approved) log.
+type ERC1155ApprovalForAllEvent struct {
+	Token       common.Address
+	Account     common.Address
+	Operator    common.Address
+	Approved    bool
+	BlockNumber uint64
+	TxHash      common.Hash
+	LogIndex    uint
+	Removed     bool
+}
+
+// ERC1155URIEvent is a decoded URI(value, id) log.
+type ERC1155URIEvent struct {
+	Token       common.Address
+	Id          *big.Int
+	Value       string
+	BlockNumber uint64
+	TxHash      common.Hash
+	LogIndex    uint
+	Removed     bool
+}
+
+// FilterERC1155Transfers returns the TransferSingle and TransferBatch events of tokens in [fromBlock, toBlock].
+// With flatten set every TransferBatch is split into one event per id. Empty filters match any value.
+func FilterERC1155Transfers(ctx context.Context, cli *ethclient.Client, tokens, operators, from, to []string, fromBlock, toBlock *big.Int, flatten bool) ([]ERC1155TransferEvent, error) {
+	return filterEvents(ctx, cli, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: toAddresses(tokens),
+		Topics: [][]common.Hash{
+			{erc1155ABI.Events["TransferSingle"].ID, erc1155ABI.Events["TransferBatch"].ID},
+			addressTopics(operators), addressTopics(from), addressTopics(to),
+		},
+	}, func(log types.Log) ([]ERC1155TransferEvent, error) {
+		events, err := decodeERC1155Transfer(log)
+		if err != nil || !flatten {
+			return events, err
+		}
+		return flattenERC1155Transfers(events), nil
+	})
+}
+
+// FilterERC1155ApprovalForAll returns the ApprovalForAll events of tokens in [fromBlock, toBlock].
+func FilterERC1155ApprovalForAll(ctx context.Context, cli *ethclient.Client, tokens, accounts, operators []string, fromBlock, toBlock *big.Int) ([]ERC1155ApprovalForAllEvent, error) {
+	return filterEvents(ctx, cli, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: toAddresses(tokens),
+		Topics:    [][]common.Hash{{erc1155ABI.Events["ApprovalForAll"].ID}, addressTopics(accounts), addressTopics(operators)},
+	}, decodeERC1155ApprovalForAll)
+}
+
+// FilterERC1155URI returns the URI events of tokens in [fromBlock, toBlock].
+func FilterERC1155URI(ctx context.Context, cli *ethclient.Client, tokens []string, ids []*big.Int, fromBlock, toBlock *big.Int) ([]ERC1155URIEvent, error) {
+	return filterEvents(ctx, cli, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: toAddresses(tokens),
+		Topics:    [][]common.Hash{{erc1155ABI.Events["URI"].ID}, bigTopics(ids)},
+	}, decodeERC1155URI)
+}
+
+// WatchERC1155Transfers streams new TransferSingle and TransferBatch events of tokens into sink.
+// It needs a websocket or IPC client.
+func WatchERC1155Transfers(ctx context.Context, cli *ethclient.Client, tokens, operators, from, to []string, flatten bool, sink chan<- ERC1155TransferEvent) (ethereum.Subscription, error) {
+	return watchEvents(ctx, cli, ethereum.FilterQuery{
+		Addresses: toAddresses(tokens),
+		Topics: [][]common.Hash{
+			{erc1155ABI.Events["TransferSingle"].ID, erc1155ABI.Events["TransferBatch"].ID},
+			addressTopics(operators), addressTopics(from), addressTopics(to),
+		},
+	}, func(log types.Log) ([]ERC1155TransferEvent, error) {
+		events, err := decodeERC1155Transfer(log)
+		if err != nil || !flatten {
+			return events, err
+		}
+		return flattenERC1155Transfers(events), nil
+	}, sink)
+}
+
+// WatchERC1155ApprovalForAll streams new ApprovalForAll events of tokens into sink. It needs a websocket or IPC client.
+func WatchERC1155ApprovalForAll(ctx context.Context, cli *ethclient.Client, tokens, accounts, operators []string, sink chan<- ERC1155ApprovalForAllEvent) (ethereum.Subscription, error) {
+	return watchEvents(ctx, cli, ethereum.FilterQuery{
+		Addresses: toAddresses(tokens),
+		Topics:    [][]common.Hash{{erc1155ABI.Events["ApprovalForAll"].ID}, addressTopics(accounts), addressTopics(operators)},
+	}, decodeERC1155ApprovalForAll, sink)
+}
+
+// WatchERC1155URI streams new URI events of tokens into sink. It needs a websocket or IPC client.
+func WatchERC1155URI(ctx context.Context, cli *ethclient.Client, tokens []string, ids []*big.Int, sink chan<- ERC1155URIEvent) (ethereum.Subscription, error) {
+	return watchEvents(ctx, cli, ethereum.FilterQuery{
+		Addresses: toAddresses(tokens),
+		Topics:    [][]common.Hash{{erc1155ABI.Events["URI"].ID}, bigTopics(ids)},
+	}, decodeERC1155URI, sink)
+}
+
+// decodeERC1155Transfer decodes a TransferSingle or TransferBatch log into one event.
+func decodeERC1155Transfer(log types.Log) ([]ERC1155TransferEvent, error) {
+	if len(log.Topics) != 4 {
+		return nil, nil
+	}
+	from, to := HashToAddress(log.Topics[2]), HashToAddress(log.Topics[3])
+	ev := ERC1155TransferEvent{
+		Token:       log.Address,
+		Operator:    HashToAddress(log.Topics[1]),
+		From:        from,
+		To:          to,
+		Kind:        ClassifyTransfer(from, to),
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+		Removed:     log.Removed,
+	}
+
+	switch log.Topics[0] {
+	case erc1155ABI.Events["TransferSingle"].ID:
+		results, err := unpackLog(erc1155ABI, "TransferSingle", log)
+		if err != nil {
+			return nil, err
+		}
+		id, err := resultAt[*big.Int]("TransferSingle", results, 0)
+		if err != nil {
+			return nil, err
+		}
+		value, err := resultAt[*big.Int]("TransferSingle", results, 1)
+		if err != nil {
+			return nil, err
+		}
+		ev.Ids, ev.Values = []*big.Int{id}, []*big.Int{value}
+	case erc1155ABI.Events["TransferBatch"].ID:
+		results, err := unpackLog(erc1155ABI, "TransferBatch", log)
+		if err != nil {
+			return nil, err
+		}
+		ids, err := resultAt[[]*big.Int]("TransferBatch", results, 0)
+		if err != nil {
+			return nil, err
+		}
+		values, err := resultAt[[]*big.Int]("TransferBatch", results, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) != len(values) {
+			return nil, fmt.Errorf("%w: TransferBatch has %d ids and %d values", ErrUnexpectedResult, len(ids), len(values))
+		}
+		ev.Ids, ev.Values, ev.Batch = ids, values, true
+	default:
+		return nil, nil
+	}
+	return []ERC1155TransferEvent{ev}, nil
+}
+
+// flattenERC1155Transfers splits every TransferBatch event into one event per id.
+func flattenERC1155Transfers(events []ERC1155TransferEvent) []ERC1155TransferEvent {
+	out := make([]ERC1155TransferEvent, 0, len(events))
+	for _, ev := range events {
+		if !ev.Batch {
+			out = append(out, ev)
+			continue
+		}
+		for i := range ev.Ids {
+			row := ev
+			row.Ids = []*big.Int{ev.Ids[i]}
+			row.Values = []*big.Int{ev.Values[i]}
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
+func decodeERC1155ApprovalForAll(log types.Log) ([]ERC1155ApprovalForAllEvent, error) {
+	if len(log.Topics) != 3 || log.Topics[0] != erc1155ABI.Events["ApprovalForAll"].ID {
+		return nil, nil
+	}
+	results, err := unpackLog(erc1155ABI, "ApprovalForAll", log)
+	if err != nil {
+		return nil, err
+	}
+	approved, err := resultAt[bool]("ApprovalForAll", results, 0)
+	if err != nil {
+		return nil, err
+	}
+	return []ERC1155ApprovalForAllEvent{{
+		Token:       log.Address,
+		Account:     HashToAddress(log.Topics[1]),
+		Operator:    HashToAddress(log.Topics[2]),
+		Approved:    approved,
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+		Removed:     log.Removed,
+	}}, nil
+}
+
+func decodeERC1155URI(log types.Log) ([]ERC1155URIEvent, error) {
+	if len(log.Topics) != 2 || log.Topics[0] != erc1155ABI.Events["URI"].ID {
+		return nil, nil
+	}
+	results, err := unpackLog(erc1155ABI, "URI", log)
+	if err != nil {
+		return nil, err
+	}
+	value, err := resultAt[string]("URI", results, 0)
+	if err != nil {
+		return nil, err
+	}
+	return []ERC1155URIEvent{{
+		Token:       log.Address,
+		Id:          HashToBigInt(log.Topics[1]),
+		Value:       value,
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+		Removed:     log.Removed,
+	}}, nil
+}
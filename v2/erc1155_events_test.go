@@ -0,0 +1,98 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func Test_FilterERC1155Transfers(t *testing.T) {
+	ins, _ := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
+	single, _ := ins.Events["TransferSingle"].Inputs.NonIndexed().Pack(big.NewInt(1), big.NewInt(10))
+	batch, _ := ins.Events["TransferBatch"].Inputs.NonIndexed().Pack(
+		[]*big.Int{big.NewInt(2), big.NewInt(3)}, []*big.Int{big.NewInt(20), big.NewInt(30)})
+	logs := []types.Log{
+		{
+			Address: exampleToken,
+			Topics:  []common.Hash{ins.Events["TransferSingle"].ID, addressTopic(exampleAddress), {}, addressTopic(exampleAddress)},
+			Data:    single,
+			Index:   0,
+		},
+		{
+			Address: exampleToken,
+			Topics:  []common.Hash{ins.Events["TransferBatch"].ID, addressTopic(exampleAddress), addressTopic(exampleAddress), {}},
+			Data:    batch,
+			Index:   1,
+		},
+	}
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_getLogs": func(params []json.RawMessage) (interface{}, *mockError) {
+			return logs, nil
+		},
+	})
+	cli := mock.client(t)
+
+	events, err := FilterERC1155Transfers(context.Background(), cli, []string{exampleToken.Hex()}, nil, nil, nil, nil, nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 2 || events[0].Kind != TransferKindMint || events[1].Kind != TransferKindBurn || len(events[1].Ids) != 2 {
+		t.Fatalf("unexpected events %+v", events)
+	}
+
+	events, err = FilterERC1155Transfers(context.Background(), cli, []string{exampleToken.Hex()}, nil, nil, nil, nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 3 {
+		t.Fatalf("expected 3 flattened rows, got %d", len(events))
+	}
+	if !events[2].Batch || events[2].Ids[0].Int64() != 3 || events[2].Values[0].Int64() != 30 || events[2].LogIndex != 1 {
+		t.Fatalf("unexpected flattened row %+v", events[2])
+	}
+}
+
+func Test_FilterERC721Transfers(t *testing.T) {
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_getLogs": func(params []json.RawMessage) (interface{}, *mockError) {
+			return []types.Log{{
+				Address: exampleToken,
+				Topics:  []common.Hash{transferTopic, {}, addressTopic(exampleAddress), common.BigToHash(big.NewInt(9))},
+			}, {
+				// an Approval log has the same shape and must not be decoded as a transfer
+				Address: exampleToken,
+				Topics:  []common.Hash{erc721ABI.Events["Approval"].ID, addressTopic(exampleAddress), {}, common.BigToHash(big.NewInt(9))},
+			}}, nil
+		},
+	})
+	events, err := FilterERC721Transfers(context.Background(), mock.client(t), nil, nil, nil, []*big.Int{big.NewInt(9)}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].TokenId.Int64() != 9 || events[0].Kind != TransferKindMint || events[0].To != exampleAddress {
+		t.Fatalf("unexpected events %+v", events)
+	}
+}
+
+func Test_FilterERC1155TransfersMalformed(t *testing.T) {
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_getLogs": func(params []json.RawMessage) (interface{}, *mockError) {
+			return []types.Log{{
+				Address: exampleToken,
+				Topics:  []common.Hash{erc1155ABI.Events["TransferSingle"].ID, addressTopic(exampleAddress), {}, addressTopic(exampleAddress)},
+				Data:    []byte{0x01},
+			}}, nil
+		},
+	})
+	events, err := FilterERC1155Transfers(context.Background(), mock.client(t), nil, nil, nil, nil, nil, nil, true)
+	if !errors.Is(err, ErrUnexpectedResult) {
+		t.Fatalf("expected ErrUnexpectedResult, got %+v %v", events, err)
+	}
+}
@@ -0,0 +1,40 @@
+package ethcli
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	InterfaceIdERC165             = [4]byte{0x01, 0xff, 0xc9, 0xa7}
+	InterfaceIdInvalid            = [4]byte{0xff, 0xff, 0xff, 0xff}
+	InterfaceIdERC721             = [4]byte{0x80, 0xac, 0x58, 0xcd}
+	InterfaceIdERC721Metadata     = [4]byte{0x5b, 0x5e, 0x13, 0x9f}
+	InterfaceIdERC721Enumerable   = [4]byte{0x78, 0x0e, 0x9d, 0x63}
+	InterfaceIdERC1155            = [4]byte{0xd9, 0xb6, 0x7a, 0x26}
+	InterfaceIdERC1155MetadataURI = [4]byte{0x0e, 0x89, 0x34, 0x1c}
+	InterfaceIdERC2981            = [4]byte{0x2a, 0x55, 0x20, 0x5a}
+	InterfaceIdERC4906            = [4]byte{0x49, 0x06, 0x49, 0x06}
+	InterfaceIdERC5192            = [4]byte{0xb4, 0x5a, 0x3c, 0x0e}
+)
+
+// SupportsInterface calls ERC165 supportsInterface(interfaceId) on contract.
+func SupportsInterface(ctx context.Context, cli *ethclient.Client, contract string, interfaceId [4]byte, blockNumber *big.Int) (bool, error) {
+	return callResult[bool](ctx, cli, erc165ABI, contract, "supportsInterface", blockNumber, interfaceId)
+}
+
+// SupportsERC165 reports whether contract implements ERC165 as the standard requires: it supports
+// InterfaceIdERC165 and rejects InterfaceIdInvalid. Reverting calls count as not supported.
+func SupportsERC165(ctx context.Context, cli *ethclient.Client, contract string, blockNumber *big.Int) (bool, error) {
+	ok, err := SupportsInterface(ctx, cli, contract, InterfaceIdERC165, blockNumber)
+	if err != nil || !ok {
+		return false, ignoreCallFailure(err)
+	}
+	invalid, err := SupportsInterface(ctx, cli, contract, InterfaceIdInvalid, blockNumber)
+	if err != nil {
+		return false, ignoreCallFailure(err)
+	}
+	return !invalid, nil
+}
@@ -0,0 +1,34 @@
+package ethcli
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	openzeppelinERC20CappedAbi = `[{"inputs":[],"name":"cap","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+)
+
+// ERC20Cap returns the maximum total supply of an ERC20Capped token.
+func ERC20Cap(ctx context.Context, cli *ethclient.Client, token string, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc20CappedABI, token, "cap", blockNumber)
+}
+
+// ERC20MintableSupply returns how much can still be minted before the cap of token is reached.
+func ERC20MintableSupply(ctx context.Context, cli *ethclient.Client, token string, blockNumber *big.Int) (*big.Int, error) {
+	capacity, err := ERC20Cap(ctx, cli, token, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	supply, err := ERC20TotalSupply(ctx, cli, token, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	remaining := new(big.Int).Sub(capacity, supply)
+	if remaining.Sign() < 0 {
+		remaining.SetInt64(0)
+	}
+	return remaining, nil
+}
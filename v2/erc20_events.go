@@ -0,0 +1,126 @@
+package ethcli
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ERC20TransferEvent is a decoded ERC20 Transfer(from, to, value) log.
+type ERC20TransferEvent struct {
+	Token       common.Address
+	From        common.Address
+	To          common.Address
+	Value       *big.Int
+	Kind        TransferKind
+	BlockNumber uint64
+	TxHash      common.Hash
+	LogIndex    uint
+	Removed     bool
+}
+
+// ERC20ApprovalEvent is a decoded ERC20 Approval(owner, spender, value) log.
+type ERC20ApprovalEvent struct {
+	Token       common.Address
+	Owner       common.Address
+	Spender     common.Address
+	Value       *big.Int
+	BlockNumber uint64
+	TxHash      common.Hash
+	LogIndex    uint
+	Removed     bool
+}
+
+// FilterERC20Transfers returns the Transfer events of tokens in [fromBlock, toBlock].
+// Empty tokens, from or to match any value; a nil toBlock means latest.
+func FilterERC20Transfers(ctx context.Context, cli *ethclient.Client, tokens, from, to []string, fromBlock, toBlock *big.Int) ([]ERC20TransferEvent, error) {
+	return filterEvents(ctx, cli, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: toAddresses(tokens),
+		Topics:    [][]common.Hash{{erc20ABI.Events["Transfer"].ID}, addressTopics(from), addressTopics(to)},
+	}, decodeERC20Transfer)
+}
+
+// FilterERC20Approvals returns the Approval events of tokens in [fromBlock, toBlock].
+// Empty tokens, owners or spenders match any value; a nil toBlock means latest.
+func FilterERC20Approvals(ctx context.Context, cli *ethclient.Client, tokens, owners, spenders []string, fromBlock, toBlock *big.Int) ([]ERC20ApprovalEvent, error) {
+	return filterEvents(ctx, cli, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: toAddresses(tokens),
+		Topics:    [][]common.Hash{{erc20ABI.Events["Approval"].ID}, addressTopics(owners), addressTopics(spenders)},
+	}, decodeERC20Approval)
+}
+
+// WatchERC20Transfers streams new Transfer events of tokens into sink. It needs a websocket or IPC client.
+// Events of blocks dropped by a reorg are delivered again with Removed set.
+func WatchERC20Transfers(ctx context.Context, cli *ethclient.Client, tokens, from, to []string, sink chan<- ERC20TransferEvent) (ethereum.Subscription, error) {
+	return watchEvents(ctx, cli, ethereum.FilterQuery{
+		Addresses: toAddresses(tokens),
+		Topics:    [][]common.Hash{{erc20ABI.Events["Transfer"].ID}, addressTopics(from), addressTopics(to)},
+	}, decodeERC20Transfer, sink)
+}
+
+// WatchERC20Approvals streams new Approval events of tokens into sink. It needs a websocket or IPC client.
+func WatchERC20Approvals(ctx context.Context, cli *ethclient.Client, tokens, owners, spenders []string, sink chan<- ERC20ApprovalEvent) (ethereum.Subscription, error) {
+	return watchEvents(ctx, cli, ethereum.FilterQuery{
+		Addresses: toAddresses(tokens),
+		Topics:    [][]common.Hash{{erc20ABI.Events["Approval"].ID}, addressTopics(owners), addressTopics(spenders)},
+	}, decodeERC20Approval, sink)
+}
+
+// decodeERC20Transfer decodes a Transfer log, skipping the ERC721 variant that indexes the third argument.
+func decodeERC20Transfer(log types.Log) ([]ERC20TransferEvent, error) {
+	if len(log.Topics) != 3 || log.Topics[0] != erc20ABI.Events["Transfer"].ID {
+		return nil, nil
+	}
+	results, err := unpackLog(erc20ABI, "Transfer", log)
+	if err != nil {
+		return nil, err
+	}
+	value, err := resultAt[*big.Int]("Transfer", results, 0)
+	if err != nil {
+		return nil, err
+	}
+	from, to := HashToAddress(log.Topics[1]), HashToAddress(log.Topics[2])
+	return []ERC20TransferEvent{{
+		Token:       log.Address,
+		From:        from,
+		To:          to,
+		Value:       value,
+		Kind:        ClassifyTransfer(from, to),
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+		Removed:     log.Removed,
+	}}, nil
+}
+
+func decodeERC20Approval(log types.Log) ([]ERC20ApprovalEvent, error) {
+	if len(log.Topics) != 3 || log.Topics[0] != erc20ABI.Events["Approval"].ID {
+		return nil, nil
+	}
+	results, err := unpackLog(erc20ABI, "Approval", log)
+	if err != nil {
+		return nil, err
+	}
+	value, err := resultAt[*big.Int]("Approval", results, 0)
+	if err != nil {
+		return nil, err
+	}
+	return []ERC20ApprovalEvent{{
+		Token:       log.Address,
+		Owner:       HashToAddress(log.Topics[1]),
+		Spender:     HashToAddress(log.Topics[2]),
+		Value:       value,
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+		Removed:     log.Removed,
+	}}, nil
+}
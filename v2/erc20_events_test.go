@@ -0,0 +1,90 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	transferTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+	approvalTopic = crypto.Keccak256Hash([]byte("Approval(address,address,uint256)"))
+)
+
+func addressTopic(addr common.Address) common.Hash {
+	return common.BytesToHash(addr.Bytes())
+}
+
+func Test_FilterERC20Transfers(t *testing.T) {
+	other := common.HexToAddress("0x5555555555555555555555555555555555555555")
+	logs := []types.Log{
+		{
+			Address:     exampleToken,
+			Topics:      []common.Hash{transferTopic, addressTopic(exampleAddress), addressTopic(other)},
+			Data:        common.LeftPadBytes(big.NewInt(42).Bytes(), 32),
+			BlockNumber: 10,
+			TxHash:      common.HexToHash("0x01"),
+			Index:       3,
+		},
+		{
+			// ERC721 Transfer shares the signature but indexes the token id.
+			Address:     other,
+			Topics:      []common.Hash{transferTopic, addressTopic(exampleAddress), addressTopic(other), common.BigToHash(big.NewInt(1))},
+			BlockNumber: 11,
+			TxHash:      common.HexToHash("0x02"),
+		},
+	}
+	var query map[string]interface{}
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_getLogs": func(params []json.RawMessage) (interface{}, *mockError) {
+			_ = json.Unmarshal(params[0], &query)
+			return logs, nil
+		},
+	})
+
+	events, err := FilterERC20Transfers(context.Background(), mock.client(t), []string{exampleToken.Hex()},
+		[]string{exampleAddress.Hex()}, nil, big.NewInt(1), big.NewInt(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	ev := events[0]
+	if ev.From != exampleAddress || ev.To != other || ev.Value.Int64() != 42 || ev.BlockNumber != 10 || ev.LogIndex != 3 {
+		t.Fatalf("unexpected event %+v", ev)
+	}
+
+	topics := query["topics"].([]interface{})
+	if topics[0].([]interface{})[0].(string) != transferTopic.Hex() || topics[1].([]interface{})[0].(string) != addressTopic(exampleAddress).Hex() {
+		t.Fatalf("unexpected topics %v", topics)
+	}
+	if query["fromBlock"] != "0x1" || query["toBlock"] != "0x64" {
+		t.Fatalf("unexpected range %v", query)
+	}
+}
+
+func Test_FilterERC20Approvals(t *testing.T) {
+	spender := common.HexToAddress("0x6666666666666666666666666666666666666666")
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_getLogs": func(params []json.RawMessage) (interface{}, *mockError) {
+			return []types.Log{{
+				Address: exampleToken,
+				Topics:  []common.Hash{approvalTopic, addressTopic(exampleAddress), addressTopic(spender)},
+				Data:    common.LeftPadBytes(big.NewInt(7).Bytes(), 32),
+			}}, nil
+		},
+	})
+	events, err := FilterERC20Approvals(context.Background(), mock.client(t), nil, nil, []string{spender.Hex()}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Owner != exampleAddress || events[0].Spender != spender || events[0].Value.Int64() != 7 {
+		t.Fatalf("unexpected events %+v", events)
+	}
+}
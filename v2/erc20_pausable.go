@@ -0,0 +1,32 @@
+package ethcli
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	openzeppelinERC20PausableAbi = `[{"inputs":[],"name":"pause","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[],"name":"unpause","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[],"name":"paused","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"address","name":"account","type":"address"}],"name":"Paused","type":"event"},{"anonymous":false,"inputs":[{"indexed":false,"internalType":"address","name":"account","type":"address"}],"name":"Unpaused","type":"event"}]`
+)
+
+func ERC20Pause(ctx context.Context, cli *ethclient.Client, token, key string) (string, error) {
+	return sendContract(ctx, cli, key, erc20PausableABI, token, "pause")
+}
+
+func ERC20Unpause(ctx context.Context, cli *ethclient.Client, token, key string) (string, error) {
+	return sendContract(ctx, cli, key, erc20PausableABI, token, "unpause")
+}
+
+func ERC20Paused(ctx context.Context, cli *ethclient.Client, token string, blockNumber *big.Int) (bool, error) {
+	return callResult[bool](ctx, cli, erc20PausableABI, token, "paused", blockNumber)
+}
+
+func ERC20PauseData() ([]byte, error) {
+	return erc20PausableABI.Pack("pause")
+}
+
+func ERC20UnpauseData() ([]byte, error) {
+	return erc20PausableABI.Pack("unpause")
+}
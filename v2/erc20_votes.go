@@ -0,0 +1,193 @@
+package ethcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+var (
+	openzeppelinERC20VotesAbi = `[{"inputs":[{"internalType":"address","name":"delegatee","type":"address"}],"name":"delegate","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"delegatee","type":"address"},{"internalType":"uint256","name":"nonce","type":"uint256"},{"internalType":"uint256","name":"expiry","type":"uint256"},{"internalType":"uint8","name":"v","type":"uint8"},{"internalType":"bytes32","name":"r","type":"bytes32"},{"internalType":"bytes32","name":"s","type":"bytes32"}],"name":"delegateBySig","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"account","type":"address"}],"name":"delegates","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"account","type":"address"}],"name":"getVotes","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"account","type":"address"},{"internalType":"uint256","name":"timepoint","type":"uint256"}],"name":"getPastVotes","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"timepoint","type":"uint256"}],"name":"getPastTotalSupply","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"account","type":"address"}],"name":"numCheckpoints","outputs":[{"internalType":"uint32","name":"","type":"uint32"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"account","type":"address"},{"internalType":"uint32","name":"pos","type":"uint32"}],"name":"checkpoints","outputs":[{"components":[{"internalType":"uint48","name":"_key","type":"uint48"},{"internalType":"uint208","name":"_value","type":"uint208"}],"internalType":"struct Checkpoints.Checkpoint208","name":"","type":"tuple"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"owner","type":"address"}],"name":"nonces","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"clock","outputs":[{"internalType":"uint48","name":"","type":"uint48"}],"stateMutability":"view","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"delegator","type":"address"},{"indexed":true,"internalType":"address","name":"fromDelegate","type":"address"},{"indexed":true,"internalType":"address","name":"toDelegate","type":"address"}],"name":"DelegateChanged","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"delegate","type":"address"},{"indexed":false,"internalType":"uint256","name":"previousVotes","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"newVotes","type":"uint256"}],"name":"DelegateVotesChanged","type":"event"}]`
+)
+
+// Delegation is the message signed for ERC20Votes delegateBySig.
+type Delegation struct {
+	Delegatee common.Address
+	Nonce     *big.Int
+	Expiry    *big.Int
+}
+
+// VotesCheckpoint is one entry of an account's vote history. Timepoint is a block number or,
+// for tokens with a timestamp clock, a unix time.
+type VotesCheckpoint struct {
+	Timepoint *big.Int
+	Votes     *big.Int
+}
+
+func ERC20Delegate(ctx context.Context, cli *ethclient.Client, token, key, delegatee string) (string, error) {
+	return sendContract(ctx, cli, key, erc20VotesABI, token, "delegate", common.HexToAddress(delegatee))
+}
+
+// ERC20DelegateBySig submits a delegation signed with SignDelegation; key only pays for the transaction.
+func ERC20DelegateBySig(ctx context.Context, cli *ethclient.Client, token, key string, delegation Delegation, signature []byte) (string, error) {
+	data, err := ERC20DelegateBySigData(delegation, signature)
+	if err != nil {
+		return "", err
+	}
+	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(data), "0", 0)
+}
+
+func ERC20Delegates(ctx context.Context, cli *ethclient.Client, token, account string, blockNumber *big.Int) (string, error) {
+	delegatee, err := callResult[common.Address](ctx, cli, erc20VotesABI, token, "delegates", blockNumber, common.HexToAddress(account))
+	if err != nil {
+		return "", err
+	}
+	return delegatee.Hex(), nil
+}
+
+func ERC20GetVotes(ctx context.Context, cli *ethclient.Client, token, account string, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc20VotesABI, token, "getVotes", blockNumber, common.HexToAddress(account))
+}
+
+// ERC20GetPastVotes returns the votes of account at timepoint, which must be in the past of the token's clock.
+func ERC20GetPastVotes(ctx context.Context, cli *ethclient.Client, token, account string, timepoint *big.Int, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc20VotesABI, token, "getPastVotes", blockNumber, common.HexToAddress(account), timepoint)
+}
+
+func ERC20GetPastTotalSupply(ctx context.Context, cli *ethclient.Client, token string, timepoint *big.Int, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc20VotesABI, token, "getPastTotalSupply", blockNumber, timepoint)
+}
+
+func ERC20NumCheckpoints(ctx context.Context, cli *ethclient.Client, token, account string, blockNumber *big.Int) (uint32, error) {
+	return callResult[uint32](ctx, cli, erc20VotesABI, token, "numCheckpoints", blockNumber, common.HexToAddress(account))
+}
+
+func ERC20Checkpoint(ctx context.Context, cli *ethclient.Client, token, account string, pos uint32, blockNumber *big.Int) (*VotesCheckpoint, error) {
+	results, err := callContract(ctx, cli, erc20VotesABI, token, "checkpoints", blockNumber, common.HexToAddress(account), pos)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("%w: checkpoints returned no values", ErrUnexpectedResult)
+	}
+	checkpoint, err := toVotesCheckpoint(results[0])
+	if err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// ERC20Checkpoints returns the whole vote history of account, oldest first, fetched in one batch.
+func ERC20Checkpoints(ctx context.Context, cli *ethclient.Client, token, account string, blockNumber *big.Int) ([]VotesCheckpoint, error) {
+	n, err := ERC20NumCheckpoints(ctx, cli, token, account, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	calls := make([]probeCall, n)
+	for i := range calls {
+		calls[i] = probeCall{erc20VotesABI, "checkpoints", []interface{}{common.HexToAddress(account), uint32(i)}}
+	}
+	results, err := probeContract(ctx, cli, token, calls, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make([]VotesCheckpoint, 0, len(results))
+	for i, result := range results {
+		if result == nil {
+			return nil, fmt.Errorf("%w: checkpoint %d", ErrUnexpectedResult, i)
+		}
+		checkpoint, err := toVotesCheckpoint(result[0])
+		if err != nil {
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+	return checkpoints, nil
+}
+
+// ERC20VotesNonce returns the nonce the next delegateBySig (or ERC2612 permit) signature of account must use.
+func ERC20VotesNonce(ctx context.Context, cli *ethclient.Client, token, account string, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc20VotesABI, token, "nonces", blockNumber, common.HexToAddress(account))
+}
+
+func ERC20DelegateData(delegatee string) ([]byte, error) {
+	return erc20VotesABI.Pack("delegate", common.HexToAddress(delegatee))
+}
+
+// ERC20DelegateBySigData encodes delegateBySig, splitting the 65-byte signature into v, r and s.
+func ERC20DelegateBySigData(delegation Delegation, signature []byte) ([]byte, error) {
+	if len(signature) != 65 {
+		return nil, errors.New("invalid signature length")
+	}
+	v := signature[64]
+	if v < 27 {
+		v += 27
+	}
+	var r, s [32]byte
+	copy(r[:], signature[:32])
+	copy(s[:], signature[32:64])
+	return erc20VotesABI.Pack("delegateBySig", delegation.Delegatee, delegation.Nonce, delegation.Expiry, v, r, s)
+}
+
+// DelegationTypedData builds the EIP-712 payload signed for delegateBySig. name is the token's EIP-712
+// domain name, normally its ERC20 name; the domain version is "1" as in OpenZeppelin's ERC20Permit.
+func DelegationTypedData(chainId *big.Int, token, name string, delegation Delegation) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"Delegation": {
+				{Name: "delegatee", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "expiry", Type: "uint256"},
+			},
+		},
+		PrimaryType: "Delegation",
+		Domain: apitypes.TypedDataDomain{
+			Name:              name,
+			Version:           "1",
+			ChainId:           (*math.HexOrDecimal256)(chainId),
+			VerifyingContract: common.HexToAddress(token).Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"delegatee": delegation.Delegatee.Hex(),
+			"nonce":     delegation.Nonce,
+			"expiry":    delegation.Expiry,
+		},
+	}
+}
+
+func SignDelegation(key string, chainId *big.Int, token, name string, delegation Delegation) ([]byte, error) {
+	return SignTypedData(key, DelegationTypedData(chainId, token, name, delegation))
+}
+
+// toVotesCheckpoint converts the unpacked (uint48 _key, uint208 _value) tuple of checkpoints.
+func toVotesCheckpoint(v interface{}) (VotesCheckpoint, error) {
+	field := func(rv reflect.Value, name string) *big.Int {
+		f := rv.FieldByName(name)
+		if !f.IsValid() || !f.CanInterface() {
+			return nil
+		}
+		n, _ := f.Interface().(*big.Int)
+		return n
+	}
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Struct {
+		key, value := field(rv, "Key"), field(rv, "Value")
+		if key != nil && value != nil {
+			return VotesCheckpoint{Timepoint: key, Votes: value}, nil
+		}
+	}
+	return VotesCheckpoint{}, fmt.Errorf("%w: checkpoints value is %T", ErrUnexpectedResult, v)
+}
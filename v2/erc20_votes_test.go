@@ -0,0 +1,112 @@
+package ethcli
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_SignDelegation(t *testing.T) {
+	delegation := Delegation{Delegatee: exampleAddress, Nonce: big.NewInt(0), Expiry: big.NewInt(1700000000)}
+	typedData := DelegationTypedData(big.NewInt(1), exampleToken.Hex(), "Token", delegation)
+	typeHash := common.BytesToHash(typedData.TypeHash(typedData.PrimaryType))
+	if want := common.HexToHash("0xe48329057bfd03d55e49b547132e39cffd9c1820ad7b9d4c5307691425d15adf"); typeHash != want {
+		t.Fatalf("typehash %s, want %s", typeHash.Hex(), want.Hex())
+	}
+
+	signature, err := SignDelegation(exampleKey, big.NewInt(1), exampleToken.Hex(), "Token", delegation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer, err := RecoverTypedDataSigner(typedData, signature); err != nil || signer != exampleAddress {
+		t.Fatalf("signer %s %v", signer.Hex(), err)
+	}
+
+	data, err := ERC20DelegateBySigData(delegation, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, err := erc20VotesABI.Methods["delegateBySig"].Inputs.Unpack(data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, s := args[4].([32]byte), args[5].([32]byte)
+	if args[0].(common.Address) != exampleAddress || args[3].(uint8) != signature[64] ||
+		common.Hash(r) != common.BytesToHash(signature[:32]) || common.Hash(s) != common.BytesToHash(signature[32:64]) {
+		t.Fatalf("unexpected delegateBySig args %v", args)
+	}
+	if _, err := ERC20DelegateBySigData(delegation, signature[:64]); err == nil {
+		t.Fatal("expected error for short signature")
+	}
+}
+
+func Test_ERC20Votes(t *testing.T) {
+	type checkpoint struct {
+		Key   *big.Int
+		Value *big.Int
+	}
+	history := []checkpoint{{big.NewInt(100), big.NewInt(10)}, {big.NewInt(120), big.NewInt(25)}}
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_call": mockContracts([]string{openzeppelinERC20VotesAbi, openzeppelinERC20CappedAbi, openzeppelinERC20Abi, openzeppelinERC20PausableAbi}, map[string]mockMethod{
+			"numCheckpoints": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{uint32(len(history))}, nil
+			},
+			"checkpoints": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				pos := args[1].(uint32)
+				if int(pos) >= len(history) {
+					return nil, mockRevert
+				}
+				return []interface{}{history[pos]}, nil
+			},
+			"getPastVotes": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{big.NewInt(10)}, nil
+			},
+			"delegates": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{args[0]}, nil
+			},
+			"cap": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{big.NewInt(1000)}, nil
+			},
+			"totalSupply": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{big.NewInt(400)}, nil
+			},
+			"paused": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{true}, nil
+			},
+		}),
+	})
+	cli := mock.client(t)
+	ctx := context.Background()
+	token, account := exampleToken.Hex(), exampleAddress.Hex()
+
+	checkpoints, err := ERC20Checkpoints(ctx, cli, token, account, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checkpoints) != 2 || checkpoints[1].Timepoint.Int64() != 120 || checkpoints[1].Votes.Int64() != 25 {
+		t.Fatalf("unexpected checkpoints %v", checkpoints)
+	}
+	first, err := ERC20Checkpoint(ctx, cli, token, account, 0, nil)
+	if err != nil || first.Timepoint.Int64() != 100 || first.Votes.Int64() != 10 {
+		t.Fatalf("checkpoint 0: %v %v", first, err)
+	}
+	votes, err := ERC20GetPastVotes(ctx, cli, token, account, big.NewInt(110), nil)
+	if err != nil || votes.Int64() != 10 {
+		t.Fatalf("past votes %s %v", votes, err)
+	}
+	delegatee, err := ERC20Delegates(ctx, cli, token, account, nil)
+	if err != nil || delegatee != account {
+		t.Fatalf("delegates %s %v", delegatee, err)
+	}
+
+	remaining, err := ERC20MintableSupply(ctx, cli, token, nil)
+	if err != nil || remaining.Int64() != 600 {
+		t.Fatalf("mintable supply %s %v", remaining, err)
+	}
+	paused, err := ERC20Paused(ctx, cli, token, nil)
+	if err != nil || !paused {
+		t.Fatalf("paused %v %v", paused, err)
+	}
+}
@@ -0,0 +1,124 @@
+package ethcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	openzeppelinERC2981Abi = `[{"inputs":[{"internalType":"uint256","name":"tokenId","type":"uint256"},{"internalType":"uint256","name":"salePrice","type":"uint256"}],"name":"royaltyInfo","outputs":[{"internalType":"address","name":"receiver","type":"address"},{"internalType":"uint256","name":"royaltyAmount","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"receiver","type":"address"},{"internalType":"uint96","name":"feeNumerator","type":"uint96"}],"name":"setDefaultRoyalty","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"tokenId","type":"uint256"},{"internalType":"address","name":"receiver","type":"address"},{"internalType":"uint96","name":"feeNumerator","type":"uint96"}],"name":"setTokenRoyalty","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"tokenId","type":"uint256"}],"name":"resetTokenRoyalty","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[],"name":"deleteDefaultRoyalty","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+)
+
+// ERC2981FeeDenominator is the default denominator of OpenZeppelin ERC2981 fee numerators, in basis points.
+const ERC2981FeeDenominator = 10000
+
+// ErrRoyaltyNotSupported is returned when a token does not report ERC2981 through ERC165.
+var ErrRoyaltyNotSupported = errors.New("erc2981 not supported")
+
+// ERC2981SupportsRoyalty reports whether token supports ERC2981 according to ERC165. Reverting calls count as not supported.
+func ERC2981SupportsRoyalty(ctx context.Context, cli *ethclient.Client, token string, blockNumber *big.Int) (bool, error) {
+	ok, err := SupportsInterface(ctx, cli, token, InterfaceIdERC2981, blockNumber)
+	if err != nil {
+		return false, ignoreCallFailure(err)
+	}
+	return ok, nil
+}
+
+// ERC2981RoyaltyInfo returns the royalty receiver and amount owed for selling tokenId at salePrice.
+// It returns ErrRoyaltyNotSupported when token does not support ERC2981.
+func ERC2981RoyaltyInfo(ctx context.Context, cli *ethclient.Client, token string, tokenId *big.Int, salePrice *big.Int, blockNumber *big.Int) (string, *big.Int, error) {
+	ok, err := ERC2981SupportsRoyalty(ctx, cli, token, blockNumber)
+	if err != nil {
+		return "", nil, err
+	}
+	if !ok {
+		return "", nil, ErrRoyaltyNotSupported
+	}
+
+	results, err := callContract(ctx, cli, erc2981ABI, token, "royaltyInfo", blockNumber, tokenId, salePrice)
+	if err != nil {
+		return "", nil, err
+	}
+	receiver, err := resultAt[common.Address]("royaltyInfo", results, 0)
+	if err != nil {
+		return "", nil, err
+	}
+	amount, err := resultAt[*big.Int]("royaltyInfo", results, 1)
+	if err != nil {
+		return "", nil, err
+	}
+	return receiver.Hex(), amount, nil
+}
+
+// ERC2981SaleSplit splits salePrice of tokenId into the royalty and the seller's proceeds, as a marketplace
+// settles a sale. Tokens without ERC2981 pay no royalty and the receiver is empty.
+func ERC2981SaleSplit(ctx context.Context, cli *ethclient.Client, token string, tokenId *big.Int, salePrice *big.Int, blockNumber *big.Int) (string, *big.Int, *big.Int, error) {
+	receiver, royalty, err := ERC2981RoyaltyInfo(ctx, cli, token, tokenId, salePrice, blockNumber)
+	if errors.Is(err, ErrRoyaltyNotSupported) {
+		return "", new(big.Int), new(big.Int).Set(salePrice), nil
+	}
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if royalty.Cmp(salePrice) > 0 {
+		return "", nil, nil, fmt.Errorf("royalty %s exceeds sale price %s", royalty, salePrice)
+	}
+	return receiver, royalty, new(big.Int).Sub(salePrice, royalty), nil
+}
+
+func ERC2981SetDefaultRoyalty(ctx context.Context, cli *ethclient.Client, key string, token string, receiver string, feeNumerator *big.Int) (string, error) {
+	if err := checkFeeNumerator(feeNumerator); err != nil {
+		return "", err
+	}
+	return sendContract(ctx, cli, key, erc2981ABI, token, "setDefaultRoyalty", common.HexToAddress(receiver), feeNumerator)
+}
+
+func ERC2981SetTokenRoyalty(ctx context.Context, cli *ethclient.Client, key string, token string, tokenId *big.Int, receiver string, feeNumerator *big.Int) (string, error) {
+	if err := checkFeeNumerator(feeNumerator); err != nil {
+		return "", err
+	}
+	return sendContract(ctx, cli, key, erc2981ABI, token, "setTokenRoyalty", tokenId, common.HexToAddress(receiver), feeNumerator)
+}
+
+func ERC2981ResetTokenRoyalty(ctx context.Context, cli *ethclient.Client, key string, token string, tokenId *big.Int) (string, error) {
+	return sendContract(ctx, cli, key, erc2981ABI, token, "resetTokenRoyalty", tokenId)
+}
+
+func ERC2981DeleteDefaultRoyalty(ctx context.Context, cli *ethclient.Client, key string, token string) (string, error) {
+	return sendContract(ctx, cli, key, erc2981ABI, token, "deleteDefaultRoyalty")
+}
+
+func ERC2981SetDefaultRoyaltyData(receiver string, feeNumerator *big.Int) ([]byte, error) {
+	if err := checkFeeNumerator(feeNumerator); err != nil {
+		return nil, err
+	}
+	return erc2981ABI.Pack("setDefaultRoyalty", common.HexToAddress(receiver), feeNumerator)
+}
+
+func ERC2981SetTokenRoyaltyData(tokenId *big.Int, receiver string, feeNumerator *big.Int) ([]byte, error) {
+	if err := checkFeeNumerator(feeNumerator); err != nil {
+		return nil, err
+	}
+	return erc2981ABI.Pack("setTokenRoyalty", tokenId, common.HexToAddress(receiver), feeNumerator)
+}
+
+func ERC2981ResetTokenRoyaltyData(tokenId *big.Int) ([]byte, error) {
+	return erc2981ABI.Pack("resetTokenRoyalty", tokenId)
+}
+
+func ERC2981DeleteDefaultRoyaltyData() ([]byte, error) {
+	return erc2981ABI.Pack("deleteDefaultRoyalty")
+}
+
+// checkFeeNumerator rejects numerators outside uint96; the ABI encoder does not check the range of sized ints.
+func checkFeeNumerator(feeNumerator *big.Int) error {
+	if feeNumerator == nil || feeNumerator.Sign() < 0 || feeNumerator.BitLen() > 96 {
+		return fmt.Errorf("invalid fee numerator %v", feeNumerator)
+	}
+	return nil
+}
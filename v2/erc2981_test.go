@@ -0,0 +1,102 @@
+package ethcli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_ERC2981RoyaltyInfo(t *testing.T) {
+	var (
+		royaltyToken = common.HexToAddress("0x6000000000000000000000000000000000000001")
+		plainToken   = common.HexToAddress("0x6000000000000000000000000000000000000002")
+		receiver     = common.HexToAddress("0x6000000000000000000000000000000000000003")
+	)
+	rpc := newMockRPC(t, map[string]mockHandler{
+		"eth_call": mockContracts([]string{customERC721SupportsInterface, openzeppelinERC2981Abi}, map[string]mockMethod{
+			"supportsInterface": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{to == royaltyToken && args[0].([4]byte) == InterfaceIdERC2981}, nil
+			},
+			"royaltyInfo": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				// 2.5%
+				amount := new(big.Int).Div(new(big.Int).Mul(args[1].(*big.Int), big.NewInt(250)), big.NewInt(ERC2981FeeDenominator))
+				return []interface{}{receiver, amount}, nil
+			},
+		}),
+	})
+	cli := rpc.client(t)
+	ctx := context.Background()
+
+	got, amount, err := ERC2981RoyaltyInfo(ctx, cli, royaltyToken.Hex(), big.NewInt(1), big.NewInt(10000), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != receiver.Hex() || amount.Int64() != 250 {
+		t.Fatalf("got %s %s", got, amount)
+	}
+	if _, _, err := ERC2981RoyaltyInfo(ctx, cli, plainToken.Hex(), big.NewInt(1), big.NewInt(10000), nil); !errors.Is(err, ErrRoyaltyNotSupported) {
+		t.Fatalf("got %v, want ErrRoyaltyNotSupported", err)
+	}
+
+	_, royalty, proceeds, err := ERC2981SaleSplit(ctx, cli, royaltyToken.Hex(), big.NewInt(1), big.NewInt(10000), nil)
+	if err != nil || royalty.Int64() != 250 || proceeds.Int64() != 9750 {
+		t.Fatalf("split %s %s %v", royalty, proceeds, err)
+	}
+	got, royalty, proceeds, err = ERC2981SaleSplit(ctx, cli, plainToken.Hex(), big.NewInt(1), big.NewInt(10000), nil)
+	if err != nil || got != "" || royalty.Sign() != 0 || proceeds.Int64() != 10000 {
+		t.Fatalf("split without royalty %s %s %s %v", got, royalty, proceeds, err)
+	}
+}
+
+func Test_ERC2981Data(t *testing.T) {
+	receiver := exampleAddress.Hex()
+	data, err := ERC2981SetTokenRoyaltyData(big.NewInt(7), receiver, big.NewInt(500))
+	if err != nil {
+		t.Fatal(err)
+	}
+	method, err := erc2981ABI.MethodById(data[:4])
+	if err != nil || method.Name != "setTokenRoyalty" {
+		t.Fatalf("selector %x: %v", data[:4], err)
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0].(*big.Int).Int64() != 7 || args[1].(common.Address) != exampleAddress || args[2].(*big.Int).Int64() != 500 {
+		t.Fatalf("unexpected args %v", args)
+	}
+
+	data, err = ERC2981DeleteDefaultRoyaltyData()
+	if err != nil || !bytes.Equal(data, erc2981ABI.Methods["deleteDefaultRoyalty"].ID) {
+		t.Fatalf("deleteDefaultRoyalty %x %v", data, err)
+	}
+	if _, err := ERC2981SetDefaultRoyaltyData(receiver, new(big.Int).Lsh(big.NewInt(1), 96)); err == nil {
+		t.Fatal("expected error for fee numerator overflowing uint96")
+	}
+}
+
+func Test_ERC2981SetRoyalty(t *testing.T) {
+	pool := newMockTxPool(nil)
+	rpc := newMockRPC(t, pool.handlers())
+	cli := rpc.client(t)
+	ctx := context.Background()
+
+	if _, err := ERC2981SetTokenRoyalty(ctx, cli, exampleKey, exampleToken.Hex(), big.NewInt(7), exampleAddress.Hex(), big.NewInt(500)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ERC2981SetDefaultRoyalty(ctx, cli, exampleKey, exampleToken.Hex(), exampleAddress.Hex(), big.NewInt(-1)); err == nil {
+		t.Fatal("expected error for negative fee numerator")
+	}
+	txs := pool.transactions()
+	if len(txs) != 1 {
+		t.Fatalf("%d transactions sent, want 1", len(txs))
+	}
+	data, _ := ERC2981SetTokenRoyaltyData(big.NewInt(7), exampleAddress.Hex(), big.NewInt(500))
+	if *txs[0].To() != exampleToken || !bytes.Equal(txs[0].Data(), data) {
+		t.Fatalf("setTokenRoyalty sent to %s with data %x", txs[0].To(), txs[0].Data())
+	}
+}
@@ -0,0 +1,500 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	EntryPointV06Address = "0x5FF137D4b0FDCD49DcA30c7CF57E578a026d2789"
+	EntryPointV07Address = "0x0000000071727De22E5E9d8BAf0edAc6f37da032"
+)
+
+var (
+	erc4337EntryPointAbi       = `[{"inputs":[{"internalType":"address","name":"sender","type":"address"},{"internalType":"uint192","name":"key","type":"uint192"}],"name":"getNonce","outputs":[{"internalType":"uint256","name":"nonce","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+	erc4337SimpleAccountAbi    = `[{"inputs":[{"internalType":"address","name":"dest","type":"address"},{"internalType":"uint256","name":"value","type":"uint256"},{"internalType":"bytes","name":"func","type":"bytes"}],"name":"execute","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address[]","name":"dest","type":"address[]"},{"internalType":"bytes[]","name":"func","type":"bytes[]"}],"name":"executeBatch","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+	erc4337SimpleAccountV07Abi = `[{"inputs":[{"internalType":"address[]","name":"dest","type":"address[]"},{"internalType":"uint256[]","name":"value","type":"uint256[]"},{"internalType":"bytes[]","name":"func","type":"bytes[]"}],"name":"executeBatch","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+)
+
+// UserOp is implemented by the user operation formats of the supported EntryPoint versions.
+type UserOp interface {
+	// Hash returns the userOpHash the EntryPoint at entryPoint computes on chainId.
+	// It fails if a numeric field does not fit its on-chain type.
+	Hash(entryPoint string, chainId *big.Int) (common.Hash, error)
+	// SetSignature replaces the signature field.
+	SetSignature(signature []byte)
+}
+
+// UserOperation is an EntryPoint v0.6 user operation.
+type UserOperation struct {
+	Sender               common.Address
+	Nonce                *big.Int
+	InitCode             []byte
+	CallData             []byte
+	CallGasLimit         *big.Int
+	VerificationGasLimit *big.Int
+	PreVerificationGas   *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	PaymasterAndData     []byte
+	Signature            []byte
+}
+
+// UserOperationV07 is an EntryPoint v0.7 user operation in the unpacked form used by bundler RPC.
+type UserOperationV07 struct {
+	Sender                        common.Address
+	Nonce                         *big.Int
+	Factory                       *common.Address
+	FactoryData                   []byte
+	CallData                      []byte
+	CallGasLimit                  *big.Int
+	VerificationGasLimit          *big.Int
+	PreVerificationGas            *big.Int
+	MaxFeePerGas                  *big.Int
+	MaxPriorityFeePerGas          *big.Int
+	Paymaster                     *common.Address
+	PaymasterVerificationGasLimit *big.Int
+	PaymasterPostOpGasLimit       *big.Int
+	PaymasterData                 []byte
+	Signature                     []byte
+}
+
+// PackedUserOperation is the on-chain v0.7 struct the EntryPoint receives.
+type PackedUserOperation struct {
+	Sender             common.Address
+	Nonce              *big.Int
+	InitCode           []byte
+	CallData           []byte
+	AccountGasLimits   [32]byte
+	PreVerificationGas *big.Int
+	GasFees            [32]byte
+	PaymasterAndData   []byte
+	Signature          []byte
+}
+
+// Call is a single call executed by a smart account.
+type Call struct {
+	To    common.Address
+	Value *big.Int
+	Data  []byte
+}
+
+func (op *UserOperation) Hash(entryPoint string, chainId *big.Int) (common.Hash, error) {
+	for _, v := range []struct {
+		name  string
+		value *big.Int
+	}{
+		{"nonce", op.Nonce},
+		{"callGasLimit", op.CallGasLimit},
+		{"verificationGasLimit", op.VerificationGasLimit},
+		{"preVerificationGas", op.PreVerificationGas},
+		{"maxFeePerGas", op.MaxFeePerGas},
+		{"maxPriorityFeePerGas", op.MaxPriorityFeePerGas},
+	} {
+		if err := checkUint(v.name, v.value, 256); err != nil {
+			return common.Hash{}, err
+		}
+	}
+	packed := concatWords(
+		common.LeftPadBytes(op.Sender.Bytes(), 32),
+		uint256Word(op.Nonce),
+		crypto.Keccak256(op.InitCode),
+		crypto.Keccak256(op.CallData),
+		uint256Word(op.CallGasLimit),
+		uint256Word(op.VerificationGasLimit),
+		uint256Word(op.PreVerificationGas),
+		uint256Word(op.MaxFeePerGas),
+		uint256Word(op.MaxPriorityFeePerGas),
+		crypto.Keccak256(op.PaymasterAndData),
+	)
+	return userOpHash(packed, entryPoint, chainId), nil
+}
+
+func (op *UserOperation) SetSignature(signature []byte) {
+	op.Signature = signature
+}
+
+// Pack converts the operation into the struct handed to EntryPoint v0.7. The gas limits and fees are packed
+// into 128-bit halves, so values wider than 128 bits are rejected.
+func (op *UserOperationV07) Pack() (PackedUserOperation, error) {
+	for _, v := range []struct {
+		name  string
+		value *big.Int
+		bits  int
+	}{
+		{"nonce", op.Nonce, 256},
+		{"callGasLimit", op.CallGasLimit, 128},
+		{"verificationGasLimit", op.VerificationGasLimit, 128},
+		{"preVerificationGas", op.PreVerificationGas, 256},
+		{"maxFeePerGas", op.MaxFeePerGas, 128},
+		{"maxPriorityFeePerGas", op.MaxPriorityFeePerGas, 128},
+		{"paymasterVerificationGasLimit", op.PaymasterVerificationGasLimit, 128},
+		{"paymasterPostOpGasLimit", op.PaymasterPostOpGasLimit, 128},
+	} {
+		if err := checkUint(v.name, v.value, v.bits); err != nil {
+			return PackedUserOperation{}, err
+		}
+	}
+	var initCode []byte
+	if op.Factory != nil {
+		initCode = append(op.Factory.Bytes(), op.FactoryData...)
+	}
+	var paymasterAndData []byte
+	if op.Paymaster != nil {
+		paymasterAndData = append(paymasterAndData, op.Paymaster.Bytes()...)
+		paymasterAndData = append(paymasterAndData, uint128Bytes(op.PaymasterVerificationGasLimit)...)
+		paymasterAndData = append(paymasterAndData, uint128Bytes(op.PaymasterPostOpGasLimit)...)
+		paymasterAndData = append(paymasterAndData, op.PaymasterData...)
+	}
+	packed := PackedUserOperation{
+		Sender:             op.Sender,
+		Nonce:              bigOrZero(op.Nonce),
+		InitCode:           initCode,
+		CallData:           op.CallData,
+		PreVerificationGas: bigOrZero(op.PreVerificationGas),
+		PaymasterAndData:   paymasterAndData,
+		Signature:          op.Signature,
+	}
+	copy(packed.AccountGasLimits[:16], uint128Bytes(op.VerificationGasLimit))
+	copy(packed.AccountGasLimits[16:], uint128Bytes(op.CallGasLimit))
+	copy(packed.GasFees[:16], uint128Bytes(op.MaxPriorityFeePerGas))
+	copy(packed.GasFees[16:], uint128Bytes(op.MaxFeePerGas))
+	return packed, nil
+}
+
+func (op *UserOperationV07) Hash(entryPoint string, chainId *big.Int) (common.Hash, error) {
+	p, err := op.Pack()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	packed := concatWords(
+		common.LeftPadBytes(p.Sender.Bytes(), 32),
+		uint256Word(p.Nonce),
+		crypto.Keccak256(p.InitCode),
+		crypto.Keccak256(p.CallData),
+		p.AccountGasLimits[:],
+		uint256Word(p.PreVerificationGas),
+		p.GasFees[:],
+		crypto.Keccak256(p.PaymasterAndData),
+	)
+	return userOpHash(packed, entryPoint, chainId), nil
+}
+
+func (op *UserOperationV07) SetSignature(signature []byte) {
+	op.Signature = signature
+}
+
+// SignUserOpHash signs userOpHash the way SimpleAccount-style wallets validate it,
+// i.e. as an EIP-191 personal message.
+func SignUserOpHash(key string, userOpHash common.Hash) ([]byte, error) {
+	return PersonalSign(key, userOpHash.Bytes())
+}
+
+// SignUserOperation computes the userOpHash of op for entryPoint and chainId, signs it and stores the signature in op.
+func SignUserOperation(key string, op UserOp, entryPoint string, chainId *big.Int) (common.Hash, error) {
+	hash, err := op.Hash(entryPoint, chainId)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	signature, err := SignUserOpHash(key, hash)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	op.SetSignature(signature)
+	return hash, nil
+}
+
+func EntryPointGetNonce(ctx context.Context, cli *ethclient.Client, entryPoint string, sender string, key *big.Int, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, entryPointABI, entryPoint, "getNonce", blockNumber, common.HexToAddress(sender), bigOrZero(key))
+}
+
+// SimpleAccountExecuteData encodes execute(dest, value, func), e.g. around ERC20TransferData.
+func SimpleAccountExecuteData(dest string, value *big.Int, data []byte) ([]byte, error) {
+	return simpleAccountABI.Pack("execute", common.HexToAddress(dest), bigOrZero(value), data)
+}
+
+// SimpleAccountExecuteBatchData encodes the v0.6 executeBatch(dest[], func[]), which cannot carry value.
+func SimpleAccountExecuteBatchData(calls []Call) ([]byte, error) {
+	dests := make([]common.Address, 0, len(calls))
+	funcs := make([][]byte, 0, len(calls))
+	for _, v := range calls {
+		if v.Value != nil && v.Value.Sign() != 0 {
+			return nil, errors.New("executeBatch v0.6 does not support value")
+		}
+		dests = append(dests, v.To)
+		funcs = append(funcs, v.Data)
+	}
+	return simpleAccountABI.Pack("executeBatch", dests, funcs)
+}
+
+// SimpleAccountV07ExecuteBatchData encodes the v0.7 executeBatch(dest[], value[], func[]).
+func SimpleAccountV07ExecuteBatchData(calls []Call) ([]byte, error) {
+	dests := make([]common.Address, 0, len(calls))
+	values := make([]*big.Int, 0, len(calls))
+	funcs := make([][]byte, 0, len(calls))
+	for _, v := range calls {
+		dests = append(dests, v.To)
+		values = append(values, bigOrZero(v.Value))
+		funcs = append(funcs, v.Data)
+	}
+	return simpleAccountV07ABI.Pack("executeBatch", dests, values, funcs)
+}
+
+type userOperationJSON struct {
+	Sender               common.Address `json:"sender"`
+	Nonce                *hexutil.Big   `json:"nonce"`
+	InitCode             hexutil.Bytes  `json:"initCode"`
+	CallData             hexutil.Bytes  `json:"callData"`
+	CallGasLimit         *hexutil.Big   `json:"callGasLimit"`
+	VerificationGasLimit *hexutil.Big   `json:"verificationGasLimit"`
+	PreVerificationGas   *hexutil.Big   `json:"preVerificationGas"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas"`
+	PaymasterAndData     hexutil.Bytes  `json:"paymasterAndData"`
+	Signature            hexutil.Bytes  `json:"signature"`
+}
+
+func (op UserOperation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(userOperationJSON{
+		Sender:               op.Sender,
+		Nonce:                hexBig(op.Nonce),
+		InitCode:             hexBytes(op.InitCode),
+		CallData:             hexBytes(op.CallData),
+		CallGasLimit:         hexBig(op.CallGasLimit),
+		VerificationGasLimit: hexBig(op.VerificationGasLimit),
+		PreVerificationGas:   hexBig(op.PreVerificationGas),
+		MaxFeePerGas:         hexBig(op.MaxFeePerGas),
+		MaxPriorityFeePerGas: hexBig(op.MaxPriorityFeePerGas),
+		PaymasterAndData:     hexBytes(op.PaymasterAndData),
+		Signature:            hexBytes(op.Signature),
+	})
+}
+
+func (op *UserOperation) UnmarshalJSON(input []byte) error {
+	var dec userOperationJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*op = UserOperation{
+		Sender:               dec.Sender,
+		Nonce:                (*big.Int)(dec.Nonce),
+		InitCode:             dec.InitCode,
+		CallData:             dec.CallData,
+		CallGasLimit:         (*big.Int)(dec.CallGasLimit),
+		VerificationGasLimit: (*big.Int)(dec.VerificationGasLimit),
+		PreVerificationGas:   (*big.Int)(dec.PreVerificationGas),
+		MaxFeePerGas:         (*big.Int)(dec.MaxFeePerGas),
+		MaxPriorityFeePerGas: (*big.Int)(dec.MaxPriorityFeePerGas),
+		PaymasterAndData:     dec.PaymasterAndData,
+		Signature:            dec.Signature,
+	}
+	return nil
+}
+
+type userOperationV07JSON struct {
+	Sender                        common.Address  `json:"sender"`
+	Nonce                         *hexutil.Big    `json:"nonce"`
+	Factory                       *common.Address `json:"factory,omitempty"`
+	FactoryData                   hexutil.Bytes   `json:"factoryData,omitempty"`
+	CallData                      hexutil.Bytes   `json:"callData"`
+	CallGasLimit                  *hexutil.Big    `json:"callGasLimit"`
+	VerificationGasLimit          *hexutil.Big    `json:"verificationGasLimit"`
+	PreVerificationGas            *hexutil.Big    `json:"preVerificationGas"`
+	MaxFeePerGas                  *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas          *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Paymaster                     *common.Address `json:"paymaster,omitempty"`
+	PaymasterVerificationGasLimit *hexutil.Big    `json:"paymasterVerificationGasLimit,omitempty"`
+	PaymasterPostOpGasLimit       *hexutil.Big    `json:"paymasterPostOpGasLimit,omitempty"`
+	PaymasterData                 hexutil.Bytes   `json:"paymasterData,omitempty"`
+	Signature                     hexutil.Bytes   `json:"signature"`
+}
+
+func (op UserOperationV07) MarshalJSON() ([]byte, error) {
+	enc := userOperationV07JSON{
+		Sender:               op.Sender,
+		Nonce:                hexBig(op.Nonce),
+		Factory:              op.Factory,
+		CallData:             hexBytes(op.CallData),
+		CallGasLimit:         hexBig(op.CallGasLimit),
+		VerificationGasLimit: hexBig(op.VerificationGasLimit),
+		PreVerificationGas:   hexBig(op.PreVerificationGas),
+		MaxFeePerGas:         hexBig(op.MaxFeePerGas),
+		MaxPriorityFeePerGas: hexBig(op.MaxPriorityFeePerGas),
+		Paymaster:            op.Paymaster,
+		Signature:            hexBytes(op.Signature),
+	}
+	if op.Factory != nil {
+		enc.FactoryData = hexBytes(op.FactoryData)
+	}
+	if op.Paymaster != nil {
+		enc.PaymasterVerificationGasLimit = hexBig(op.PaymasterVerificationGasLimit)
+		enc.PaymasterPostOpGasLimit = hexBig(op.PaymasterPostOpGasLimit)
+		enc.PaymasterData = hexBytes(op.PaymasterData)
+	}
+	return json.Marshal(enc)
+}
+
+func (op *UserOperationV07) UnmarshalJSON(input []byte) error {
+	var dec userOperationV07JSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*op = UserOperationV07{
+		Sender:                        dec.Sender,
+		Nonce:                         (*big.Int)(dec.Nonce),
+		Factory:                       dec.Factory,
+		FactoryData:                   dec.FactoryData,
+		CallData:                      dec.CallData,
+		CallGasLimit:                  (*big.Int)(dec.CallGasLimit),
+		VerificationGasLimit:          (*big.Int)(dec.VerificationGasLimit),
+		PreVerificationGas:            (*big.Int)(dec.PreVerificationGas),
+		MaxFeePerGas:                  (*big.Int)(dec.MaxFeePerGas),
+		MaxPriorityFeePerGas:          (*big.Int)(dec.MaxPriorityFeePerGas),
+		Paymaster:                     dec.Paymaster,
+		PaymasterVerificationGasLimit: (*big.Int)(dec.PaymasterVerificationGasLimit),
+		PaymasterPostOpGasLimit:       (*big.Int)(dec.PaymasterPostOpGasLimit),
+		PaymasterData:                 dec.PaymasterData,
+		Signature:                     dec.Signature,
+	}
+	return nil
+}
+
+// UserOperationGasEstimate is the result of eth_estimateUserOperationGas.
+// The paymaster limits are only reported by v0.7 bundlers.
+type UserOperationGasEstimate struct {
+	PreVerificationGas            *hexutil.Big `json:"preVerificationGas"`
+	VerificationGasLimit          *hexutil.Big `json:"verificationGasLimit"`
+	CallGasLimit                  *hexutil.Big `json:"callGasLimit"`
+	PaymasterVerificationGasLimit *hexutil.Big `json:"paymasterVerificationGasLimit,omitempty"`
+	PaymasterPostOpGasLimit       *hexutil.Big `json:"paymasterPostOpGasLimit,omitempty"`
+}
+
+// UserOperationReceipt is the result of eth_getUserOperationReceipt.
+type UserOperationReceipt struct {
+	UserOpHash    common.Hash    `json:"userOpHash"`
+	EntryPoint    common.Address `json:"entryPoint"`
+	Sender        common.Address `json:"sender"`
+	Nonce         *hexutil.Big   `json:"nonce"`
+	Paymaster     common.Address `json:"paymaster"`
+	ActualGasCost *hexutil.Big   `json:"actualGasCost"`
+	ActualGasUsed *hexutil.Big   `json:"actualGasUsed"`
+	Success       bool           `json:"success"`
+	Reason        string         `json:"reason"`
+	Logs          []*types.Log   `json:"logs"`
+	Receipt       *types.Receipt `json:"receipt"`
+}
+
+// BundlerClient talks to an ERC-4337 bundler over JSON-RPC.
+type BundlerClient struct {
+	c *rpc.Client
+}
+
+func NewBundlerClient(rawurl string) (*BundlerClient, error) {
+	c, err := rpc.Dial(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return &BundlerClient{c: c}, nil
+}
+
+func (b *BundlerClient) Close() {
+	b.c.Close()
+}
+
+func (b *BundlerClient) RPCClient() *rpc.Client {
+	return b.c
+}
+
+// SendUserOperation submits op to entryPoint and returns its userOpHash.
+func (b *BundlerClient) SendUserOperation(ctx context.Context, op UserOp, entryPoint string) (common.Hash, error) {
+	var hash common.Hash
+	err := b.c.CallContext(ctx, &hash, "eth_sendUserOperation", op, common.HexToAddress(entryPoint))
+	return hash, err
+}
+
+func (b *BundlerClient) EstimateUserOperationGas(ctx context.Context, op UserOp, entryPoint string) (*UserOperationGasEstimate, error) {
+	var estimate UserOperationGasEstimate
+	if err := b.c.CallContext(ctx, &estimate, "eth_estimateUserOperationGas", op, common.HexToAddress(entryPoint)); err != nil {
+		return nil, err
+	}
+	return &estimate, nil
+}
+
+// GetUserOperationReceipt returns the receipt of an included user operation, or ethereum.NotFound while it is pending.
+func (b *BundlerClient) GetUserOperationReceipt(ctx context.Context, userOpHash common.Hash) (*UserOperationReceipt, error) {
+	var receipt *UserOperationReceipt
+	if err := b.c.CallContext(ctx, &receipt, "eth_getUserOperationReceipt", userOpHash); err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return nil, ethereum.NotFound
+	}
+	return receipt, nil
+}
+
+func (b *BundlerClient) SupportedEntryPoints(ctx context.Context) ([]common.Address, error) {
+	var entryPoints []common.Address
+	err := b.c.CallContext(ctx, &entryPoints, "eth_supportedEntryPoints")
+	return entryPoints, err
+}
+
+func userOpHash(packed []byte, entryPoint string, chainId *big.Int) common.Hash {
+	return crypto.Keccak256Hash(concatWords(
+		crypto.Keccak256(packed),
+		common.LeftPadBytes(common.HexToAddress(entryPoint).Bytes(), 32),
+		uint256Word(chainId),
+	))
+}
+
+func concatWords(words ...[]byte) []byte {
+	bz := make([]byte, 0, 32*len(words))
+	for _, v := range words {
+		bz = append(bz, v...)
+	}
+	return bz
+}
+
+func uint256Word(v *big.Int) []byte {
+	return common.LeftPadBytes(bigOrZero(v).Bytes(), 32)
+}
+
+func uint128Bytes(v *big.Int) []byte {
+	return common.LeftPadBytes(bigOrZero(v).Bytes(), 16)
+}
+
+// checkUint reports an error if v, treated as zero when nil, does not fit an unsigned integer of bits.
+func checkUint(name string, v *big.Int, bits int) error {
+	if v != nil && (v.Sign() < 0 || v.BitLen() > bits) {
+		return fmt.Errorf("%s %s out of uint%d range", name, v, bits)
+	}
+	return nil
+}
+
+func bigOrZero(v *big.Int) *big.Int {
+	if v == nil {
+		return new(big.Int)
+	}
+	return v
+}
+
+func hexBig(v *big.Int) *hexutil.Big {
+	return (*hexutil.Big)(bigOrZero(v))
+}
+
+func hexBytes(bz []byte) hexutil.Bytes {
+	if bz == nil {
+		return hexutil.Bytes{}
+	}
+	return bz
+}
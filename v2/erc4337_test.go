@@ -0,0 +1,164 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func exampleUserOperationV07(t *testing.T) *UserOperationV07 {
+	transfer, err := ERC20TransferData(exampleAddress.Hex(), "1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	callData, err := SimpleAccountExecuteData(exampleToken.Hex(), nil, transfer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	paymaster := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	return &UserOperationV07{
+		Sender:                        common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		Nonce:                         big.NewInt(7),
+		CallData:                      callData,
+		CallGasLimit:                  big.NewInt(100000),
+		VerificationGasLimit:          big.NewInt(200000),
+		PreVerificationGas:            big.NewInt(50000),
+		MaxFeePerGas:                  big.NewInt(3e9),
+		MaxPriorityFeePerGas:          big.NewInt(1e9),
+		Paymaster:                     &paymaster,
+		PaymasterVerificationGasLimit: big.NewInt(30000),
+		PaymasterPostOpGasLimit:       big.NewInt(10000),
+		PaymasterData:                 []byte{0x01},
+	}
+}
+
+// The expected hashes were computed outside this package following EntryPoint.getUserOpHash:
+// keccak256(abi.encode(keccak256(pack(op)), entryPoint, chainId)).
+func Test_UserOperationHash(t *testing.T) {
+	factory := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	paymaster := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	sender := common.HexToAddress("0x1111111111111111111111111111111111111111")
+
+	v06 := &UserOperation{
+		Sender:               sender,
+		Nonce:                big.NewInt(7),
+		InitCode:             append(factory.Bytes(), 0x5f, 0xbf, 0xb9, 0xcf),
+		CallData:             []byte{0xb6, 0x1d, 0x27, 0xf6},
+		CallGasLimit:         big.NewInt(100000),
+		VerificationGasLimit: big.NewInt(200000),
+		PreVerificationGas:   big.NewInt(50000),
+		MaxFeePerGas:         big.NewInt(3e9),
+		MaxPriorityFeePerGas: big.NewInt(1e9),
+	}
+	hash, err := v06.Hash(EntryPointV06Address, big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := common.HexToHash("0x11cd80ff48929a3be79a9149a1d61a8f11bdea13a62d6a32efa6ef6583f0cb16"); hash != want {
+		t.Fatalf("v0.6 hash %s, want %s", hash.Hex(), want.Hex())
+	}
+
+	v07 := &UserOperationV07{
+		Sender:                        sender,
+		Nonce:                         big.NewInt(7),
+		Factory:                       &factory,
+		FactoryData:                   []byte{0x5f, 0xbf, 0xb9, 0xcf},
+		CallData:                      []byte{0xb6, 0x1d, 0x27, 0xf6},
+		CallGasLimit:                  big.NewInt(100000),
+		VerificationGasLimit:          big.NewInt(200000),
+		PreVerificationGas:            big.NewInt(50000),
+		MaxFeePerGas:                  big.NewInt(3e9),
+		MaxPriorityFeePerGas:          big.NewInt(1e9),
+		Paymaster:                     &paymaster,
+		PaymasterVerificationGasLimit: big.NewInt(30000),
+		PaymasterPostOpGasLimit:       big.NewInt(10000),
+		PaymasterData:                 []byte{0x01},
+	}
+	hash, err = v07.Hash(EntryPointV07Address, big.NewInt(11155111))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := common.HexToHash("0x295225920795aee61957769e3a59e6bce9157d6714e5d4926415ca2e36992344"); hash != want {
+		t.Fatalf("v0.7 hash %s, want %s", hash.Hex(), want.Hex())
+	}
+
+	v07.MaxFeePerGas = new(big.Int).Lsh(big.NewInt(1), 128)
+	if _, err := v07.Hash(EntryPointV07Address, big.NewInt(11155111)); err == nil {
+		t.Fatal("expected error for maxFeePerGas wider than 128 bits")
+	}
+	v06.Nonce = new(big.Int).Lsh(big.NewInt(1), 256)
+	if _, err := v06.Hash(EntryPointV06Address, big.NewInt(1)); err == nil {
+		t.Fatal("expected error for nonce wider than 256 bits")
+	}
+}
+
+func Test_BundlerClient(t *testing.T) {
+	op := exampleUserOperationV07(t)
+	hash, err := SignUserOperation(exampleKey, op, EntryPointV07Address, big.NewInt(11155111))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := RecoverPersonalSigner(hash.Bytes(), op.Signature)
+	if err != nil || signer != exampleAddress {
+		t.Fatalf("signature recovers to %s: %v", signer.Hex(), err)
+	}
+
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_sendUserOperation": func(params []json.RawMessage) (interface{}, *mockError) {
+			var sent UserOperationV07
+			if err := json.Unmarshal(params[0], &sent); err != nil {
+				return nil, &mockError{Code: -32602, Message: err.Error()}
+			}
+			hash, err := sent.Hash(EntryPointV07Address, big.NewInt(11155111))
+			if err != nil {
+				return nil, &mockError{Code: -32602, Message: err.Error()}
+			}
+			return hash, nil
+		},
+		"eth_estimateUserOperationGas": func(params []json.RawMessage) (interface{}, *mockError) {
+			return map[string]string{"preVerificationGas": "0xc350", "verificationGasLimit": "0x30d40", "callGasLimit": "0x186a0"}, nil
+		},
+		"eth_getUserOperationReceipt": func(params []json.RawMessage) (interface{}, *mockError) {
+			var h common.Hash
+			_ = json.Unmarshal(params[0], &h)
+			if h != hash {
+				return nil, nil
+			}
+			return map[string]interface{}{"userOpHash": h, "sender": op.Sender, "success": true, "actualGasUsed": "0x1"}, nil
+		},
+	})
+	bundler, err := NewBundlerClient(mock.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bundler.Close()
+	ctx := context.Background()
+
+	sent, err := bundler.SendUserOperation(ctx, op, EntryPointV07Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != hash {
+		t.Fatalf("bundler computed %s, want %s", sent.Hex(), hash.Hex())
+	}
+	estimate, err := bundler.EstimateUserOperationGas(ctx, op, EntryPointV07Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if estimate.CallGasLimit.ToInt().Int64() != 100000 {
+		t.Fatalf("unexpected estimate %+v", estimate)
+	}
+	receipt, err := bundler.GetUserOperationReceipt(ctx, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !receipt.Success || receipt.Sender != op.Sender {
+		t.Fatalf("unexpected receipt %+v", receipt)
+	}
+	if _, err := bundler.GetUserOperationReceipt(ctx, common.Hash{}); err == nil {
+		t.Fatal("expected not found")
+	}
+}
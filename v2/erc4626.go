@@ -0,0 +1,115 @@
+package ethcli
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	openzeppelinIERC4626Abi = `[{"inputs":[],"name":"asset","outputs":[{"internalType":"address","name":"assetTokenAddress","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"totalAssets","outputs":[{"internalType":"uint256","name":"totalManagedAssets","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"assets","type":"uint256"}],"name":"convertToShares","outputs":[{"internalType":"uint256","name":"shares","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"shares","type":"uint256"}],"name":"convertToAssets","outputs":[{"internalType":"uint256","name":"assets","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"receiver","type":"address"}],"name":"maxDeposit","outputs":[{"internalType":"uint256","name":"maxAssets","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"assets","type":"uint256"}],"name":"previewDeposit","outputs":[{"internalType":"uint256","name":"shares","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"assets","type":"uint256"},{"internalType":"address","name":"receiver","type":"address"}],"name":"deposit","outputs":[{"internalType":"uint256","name":"shares","type":"uint256"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"receiver","type":"address"}],"name":"maxMint","outputs":[{"internalType":"uint256","name":"maxShares","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"shares","type":"uint256"}],"name":"previewMint","outputs":[{"internalType":"uint256","name":"assets","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"shares","type":"uint256"},{"internalType":"address","name":"receiver","type":"address"}],"name":"mint","outputs":[{"internalType":"uint256","name":"assets","type":"uint256"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"owner","type":"address"}],"name":"maxWithdraw","outputs":[{"internalType":"uint256","name":"maxAssets","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"assets","type":"uint256"}],"name":"previewWithdraw","outputs":[{"internalType":"uint256","name":"shares","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"assets","type":"uint256"},{"internalType":"address","name":"receiver","type":"address"},{"internalType":"address","name":"owner","type":"address"}],"name":"withdraw","outputs":[{"internalType":"uint256","name":"shares","type":"uint256"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"owner","type":"address"}],"name":"maxRedeem","outputs":[{"internalType":"uint256","name":"maxShares","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"shares","type":"uint256"}],"name":"previewRedeem","outputs":[{"internalType":"uint256","name":"assets","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"uint256","name":"shares","type":"uint256"},{"internalType":"address","name":"receiver","type":"address"},{"internalType":"address","name":"owner","type":"address"}],"name":"redeem","outputs":[{"internalType":"uint256","name":"assets","type":"uint256"}],"stateMutability":"nonpayable","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"sender","type":"address"},{"indexed":true,"internalType":"address","name":"owner","type":"address"},{"indexed":false,"internalType":"uint256","name":"assets","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"shares","type":"uint256"}],"name":"Deposit","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"sender","type":"address"},{"indexed":true,"internalType":"address","name":"receiver","type":"address"},{"indexed":true,"internalType":"address","name":"owner","type":"address"},{"indexed":false,"internalType":"uint256","name":"assets","type":"uint256"},{"indexed":false,"internalType":"uint256","name":"shares","type":"uint256"}],"name":"Withdraw","type":"event"}]`
+)
+
+func ERC4626Asset(ctx context.Context, cli *ethclient.Client, vault string, blockNumber *big.Int) (string, error) {
+	asset, err := callResult[common.Address](ctx, cli, erc4626ABI, vault, "asset", blockNumber)
+	if err != nil {
+		return "", err
+	}
+	return asset.Hex(), nil
+}
+
+func ERC4626TotalAssets(ctx context.Context, cli *ethclient.Client, vault string, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc4626ABI, vault, "totalAssets", blockNumber)
+}
+
+func ERC4626ConvertToShares(ctx context.Context, cli *ethclient.Client, vault string, assets *big.Int, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc4626ABI, vault, "convertToShares", blockNumber, assets)
+}
+
+func ERC4626ConvertToAssets(ctx context.Context, cli *ethclient.Client, vault string, shares *big.Int, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc4626ABI, vault, "convertToAssets", blockNumber, shares)
+}
+
+func ERC4626PreviewDeposit(ctx context.Context, cli *ethclient.Client, vault string, assets *big.Int, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc4626ABI, vault, "previewDeposit", blockNumber, assets)
+}
+
+func ERC4626PreviewMint(ctx context.Context, cli *ethclient.Client, vault string, shares *big.Int, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc4626ABI, vault, "previewMint", blockNumber, shares)
+}
+
+func ERC4626PreviewWithdraw(ctx context.Context, cli *ethclient.Client, vault string, assets *big.Int, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc4626ABI, vault, "previewWithdraw", blockNumber, assets)
+}
+
+func ERC4626PreviewRedeem(ctx context.Context, cli *ethclient.Client, vault string, shares *big.Int, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc4626ABI, vault, "previewRedeem", blockNumber, shares)
+}
+
+func ERC4626MaxDeposit(ctx context.Context, cli *ethclient.Client, vault string, receiver string, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc4626ABI, vault, "maxDeposit", blockNumber, common.HexToAddress(receiver))
+}
+
+func ERC4626MaxMint(ctx context.Context, cli *ethclient.Client, vault string, receiver string, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc4626ABI, vault, "maxMint", blockNumber, common.HexToAddress(receiver))
+}
+
+func ERC4626MaxWithdraw(ctx context.Context, cli *ethclient.Client, vault string, owner string, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc4626ABI, vault, "maxWithdraw", blockNumber, common.HexToAddress(owner))
+}
+
+func ERC4626MaxRedeem(ctx context.Context, cli *ethclient.Client, vault string, owner string, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, erc4626ABI, vault, "maxRedeem", blockNumber, common.HexToAddress(owner))
+}
+
+// ERC4626ShareValue returns the vault shares held by holder and their value in units of the underlying asset.
+// The value uses convertToAssets, so it excludes withdrawal fees; use ERC4626PreviewRedeem for the amount a
+// redemption would pay out.
+func ERC4626ShareValue(ctx context.Context, cli *ethclient.Client, vault string, holder string, blockNumber *big.Int) (*big.Int, *big.Int, error) {
+	shares, err := callResult[*big.Int](ctx, cli, erc20ABI, vault, "balanceOf", blockNumber, common.HexToAddress(holder))
+	if err != nil {
+		return nil, nil, err
+	}
+	if shares.Sign() == 0 {
+		return shares, new(big.Int), nil
+	}
+	assets, err := ERC4626ConvertToAssets(ctx, cli, vault, shares, blockNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+	return shares, assets, nil
+}
+
+func ERC4626Deposit(ctx context.Context, cli *ethclient.Client, key string, vault string, assets *big.Int, receiver string) (string, error) {
+	return sendContract(ctx, cli, key, erc4626ABI, vault, "deposit", assets, common.HexToAddress(receiver))
+}
+
+func ERC4626Mint(ctx context.Context, cli *ethclient.Client, key string, vault string, shares *big.Int, receiver string) (string, error) {
+	return sendContract(ctx, cli, key, erc4626ABI, vault, "mint", shares, common.HexToAddress(receiver))
+}
+
+func ERC4626Withdraw(ctx context.Context, cli *ethclient.Client, key string, vault string, assets *big.Int, receiver string, owner string) (string, error) {
+	return sendContract(ctx, cli, key, erc4626ABI, vault, "withdraw", assets, common.HexToAddress(receiver), common.HexToAddress(owner))
+}
+
+func ERC4626Redeem(ctx context.Context, cli *ethclient.Client, key string, vault string, shares *big.Int, receiver string, owner string) (string, error) {
+	return sendContract(ctx, cli, key, erc4626ABI, vault, "redeem", shares, common.HexToAddress(receiver), common.HexToAddress(owner))
+}
+
+func ERC4626DepositData(assets *big.Int, receiver string) ([]byte, error) {
+	return erc4626ABI.Pack("deposit", assets, common.HexToAddress(receiver))
+}
+
+func ERC4626MintData(shares *big.Int, receiver string) ([]byte, error) {
+	return erc4626ABI.Pack("mint", shares, common.HexToAddress(receiver))
+}
+
+func ERC4626WithdrawData(assets *big.Int, receiver string, owner string) ([]byte, error) {
+	return erc4626ABI.Pack("withdraw", assets, common.HexToAddress(receiver), common.HexToAddress(owner))
+}
+
+func ERC4626RedeemData(shares *big.Int, receiver string, owner string) ([]byte, error) {
+	return erc4626ABI.Pack("redeem", shares, common.HexToAddress(receiver), common.HexToAddress(owner))
+}
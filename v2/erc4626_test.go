@@ -0,0 +1,108 @@
+package ethcli
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_ERC4626(t *testing.T) {
+	vault := common.HexToAddress("0x7000000000000000000000000000000000000001")
+	// 1 share is worth 2 assets
+	toAssets := func(shares *big.Int) *big.Int { return new(big.Int).Mul(shares, big.NewInt(2)) }
+	toShares := func(assets *big.Int) *big.Int { return new(big.Int).Div(assets, big.NewInt(2)) }
+	balances := map[common.Address]*big.Int{exampleAddress: big.NewInt(500)}
+
+	rpc := newMockRPC(t, map[string]mockHandler{
+		"eth_call": mockContracts([]string{openzeppelinIERC4626Abi, openzeppelinERC20Abi}, map[string]mockMethod{
+			"asset": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{exampleToken}, nil
+			},
+			"totalAssets": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{big.NewInt(1000)}, nil
+			},
+			"convertToAssets": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{toAssets(args[0].(*big.Int))}, nil
+			},
+			"convertToShares": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{toShares(args[0].(*big.Int))}, nil
+			},
+			"previewRedeem": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				// 1% withdrawal fee
+				assets := toAssets(args[0].(*big.Int))
+				return []interface{}{assets.Sub(assets, new(big.Int).Div(assets, big.NewInt(100)))}, nil
+			},
+			"maxWithdraw": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{toAssets(balances[args[0].(common.Address)])}, nil
+			},
+			"balanceOf": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				if b, ok := balances[args[0].(common.Address)]; ok {
+					return []interface{}{b}, nil
+				}
+				return []interface{}{new(big.Int)}, nil
+			},
+		}),
+	})
+	cli := rpc.client(t)
+	ctx := context.Background()
+
+	asset, err := ERC4626Asset(ctx, cli, vault.Hex(), nil)
+	if err != nil || asset != exampleToken.Hex() {
+		t.Fatalf("asset %s %v", asset, err)
+	}
+	total, err := ERC4626TotalAssets(ctx, cli, vault.Hex(), nil)
+	if err != nil || total.Int64() != 1000 {
+		t.Fatalf("totalAssets %s %v", total, err)
+	}
+	shares, err := ERC4626ConvertToShares(ctx, cli, vault.Hex(), big.NewInt(100), nil)
+	if err != nil || shares.Int64() != 50 {
+		t.Fatalf("convertToShares %s %v", shares, err)
+	}
+	redeemed, err := ERC4626PreviewRedeem(ctx, cli, vault.Hex(), big.NewInt(100), nil)
+	if err != nil || redeemed.Int64() != 198 {
+		t.Fatalf("previewRedeem %s %v", redeemed, err)
+	}
+	maxWithdraw, err := ERC4626MaxWithdraw(ctx, cli, vault.Hex(), exampleAddress.Hex(), nil)
+	if err != nil || maxWithdraw.Int64() != 1000 {
+		t.Fatalf("maxWithdraw %s %v", maxWithdraw, err)
+	}
+
+	shares, assets, err := ERC4626ShareValue(ctx, cli, vault.Hex(), exampleAddress.Hex(), nil)
+	if err != nil || shares.Int64() != 500 || assets.Int64() != 1000 {
+		t.Fatalf("share value %s %s %v", shares, assets, err)
+	}
+	shares, assets, err = ERC4626ShareValue(ctx, cli, vault.Hex(), exampleToken.Hex(), nil)
+	if err != nil || shares.Sign() != 0 || assets.Sign() != 0 {
+		t.Fatalf("share value without shares %s %s %v", shares, assets, err)
+	}
+}
+
+func Test_ERC4626Data(t *testing.T) {
+	receiver := common.HexToAddress("0x7000000000000000000000000000000000000002")
+	data, err := ERC4626RedeemData(big.NewInt(42), receiver.Hex(), exampleAddress.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	method, err := erc4626ABI.MethodById(data[:4])
+	if err != nil || method.Name != "redeem" {
+		t.Fatalf("selector %x: %v", data[:4], err)
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0].(*big.Int).Int64() != 42 || args[1].(common.Address) != receiver || args[2].(common.Address) != exampleAddress {
+		t.Fatalf("unexpected args %v", args)
+	}
+
+	decoder, err := NewDecoder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := decoder.DecodeCall(data)
+	if err != nil || decoded.Name != "redeem" {
+		t.Fatalf("decode %v %v", decoded, err)
+	}
+}
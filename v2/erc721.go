@@ -3,12 +3,8 @@ package ethcli
 import (
 	"context"
 	"math/big"
-	"strings"
 
-	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
@@ -22,290 +18,97 @@ var (
 )
 
 func ERC721BalanceOf(ctx context.Context, cli *ethclient.Client, token string, owner string, blockNumber *big.Int) (*big.Int, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC721Abi))
-	if err != nil {
-		return nil, err
-	}
-	data, _ := ins.Pack("balanceOf", common.HexToAddress(owner))
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return nil, err
-	}
-
-	results, err := ins.Unpack("balanceOf", bz)
-	if err != nil {
-		return nil, err
-	}
-
-	return results[0].(*big.Int), nil
+	return callResult[*big.Int](ctx, cli, erc721ABI, token, "balanceOf", blockNumber, common.HexToAddress(owner))
 }
 
 func ERC721OwnerOf(ctx context.Context, cli *ethclient.Client, token string, tokenId *big.Int, blockNumber *big.Int) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC721Abi))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("ownerOf", tokenId)
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
+	v, err := callResult[common.Address](ctx, cli, erc721ABI, token, "ownerOf", blockNumber, tokenId)
 	if err != nil {
 		return "", err
 	}
-
-	results, err := ins.Unpack("ownerOf", bz)
-	if err != nil {
-		return "", err
-	}
-
-	return results[0].(common.Address).Hex(), nil
+	return v.Hex(), nil
 }
 
 func ERC721SafeTransferFrom(ctx context.Context, cli *ethclient.Client, token string, key, from, to string, tokenId *big.Int) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC721Abi))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("safeTransferFrom", common.HexToAddress(from), common.HexToAddress(to), tokenId)
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(data), "0", 0)
+	return sendContract(ctx, cli, key, erc721ABI, token, "safeTransferFrom", common.HexToAddress(from), common.HexToAddress(to), tokenId)
 }
 
 func ERC721TransferFrom(ctx context.Context, cli *ethclient.Client, token string, key, from, to string, tokenId *big.Int) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC721Abi))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("transferFrom", common.HexToAddress(from), common.HexToAddress(to), tokenId)
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(data), "0", 0)
+	return sendContract(ctx, cli, key, erc721ABI, token, "transferFrom", common.HexToAddress(from), common.HexToAddress(to), tokenId)
 }
 
 func ERC721Approve(ctx context.Context, cli *ethclient.Client, token string, key, to string, tokenId *big.Int) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC721Abi))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("approve", common.HexToAddress(to), tokenId)
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(data), "0", 0)
+	return sendContract(ctx, cli, key, erc721ABI, token, "approve", common.HexToAddress(to), tokenId)
 }
 
 func ERC721GetApproved(ctx context.Context, cli *ethclient.Client, token string, tokenId *big.Int, blockNumber *big.Int) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC721Abi))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("getApproved", tokenId)
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
+	v, err := callResult[common.Address](ctx, cli, erc721ABI, token, "getApproved", blockNumber, tokenId)
 	if err != nil {
 		return "", err
 	}
-
-	results, err := ins.Unpack("getApproved", bz)
-	if err != nil {
-		return "", err
-	}
-
-	return results[0].(common.Address).Hex(), nil
+	return v.Hex(), nil
 }
 
 func ERC721SetApprovalForAll(ctx context.Context, cli *ethclient.Client, token string, key, operator string, approved bool) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC721Abi))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("setApprovalForAll", common.HexToAddress(operator), approved)
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(data), "0", 0)
+	return sendContract(ctx, cli, key, erc721ABI, token, "setApprovalForAll", common.HexToAddress(operator), approved)
 }
 
 func ERC721IsApprovedForAll(ctx context.Context, cli *ethclient.Client, token string, owner, operator string, blockNumber *big.Int) (bool, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC721Abi))
-	if err != nil {
-		return false, err
-	}
-	data, _ := ins.Pack("isApprovedForAll", common.HexToAddress(owner), common.HexToAddress(operator))
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return false, err
-	}
-
-	results, err := ins.Unpack("isApprovedForAll", bz)
-	if err != nil {
-		return false, err
-	}
-
-	return results[0].(bool), nil
+	return callResult[bool](ctx, cli, erc721ABI, token, "isApprovedForAll", blockNumber, common.HexToAddress(owner), common.HexToAddress(operator))
 }
 
 func ERC721SafeTransferFromWithData(ctx context.Context, cli *ethclient.Client, token string, key, from, to string, tokenId *big.Int, calldata []byte) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC721Abi))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("safeTransferFrom", common.HexToAddress(from), common.HexToAddress(to), tokenId, calldata)
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(data), "0", 0)
+	return sendContract(ctx, cli, key, erc721ABI, token, "safeTransferFrom", common.HexToAddress(from), common.HexToAddress(to), tokenId, calldata)
 }
 
 func ERC721Mint(ctx context.Context, cli *ethclient.Client, token string, key string, to string) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(customERC721Mint))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("mint", common.HexToAddress(to))
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(data), "0", 0)
+	return sendContract(ctx, cli, key, erc721MintABI, token, "mint", common.HexToAddress(to))
 }
 
 func ERC721MintWithTokenURI(ctx context.Context, cli *ethclient.Client, token string, key string, to string, uri string) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(customERC721MintWithURI))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("mint", common.HexToAddress(to), uri)
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(data), "0", 0)
+	return sendContract(ctx, cli, key, erc721MintWithURIABI, token, "mint", common.HexToAddress(to), uri)
 }
 
 func ERC721MintWithTokenIdAndURI(ctx context.Context, cli *ethclient.Client, token string, key string, to string, tokenId *big.Int, uri string) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(customERC721MintWithTokenIdAndURI))
-	if err != nil {
-		return "", err
-	}
-	data, err := ins.Pack("mint", common.HexToAddress(to), tokenId, uri)
-	if err != nil {
-		return "", err
-	}
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(data), "0", 0)
+	return sendContract(ctx, cli, key, erc721MintWithTokenIdAndURIABI, token, "mint", common.HexToAddress(to), tokenId, uri)
 }
 
 func ERC721Exists(ctx context.Context, cli *ethclient.Client, token string, tokenId *big.Int, blockNumber *big.Int) (bool, error) {
-	ins, err := abi.JSON(strings.NewReader(customERC721Exists))
-	if err != nil {
-		return false, err
-	}
-	data, _ := ins.Pack("exists", tokenId)
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return false, err
-	}
-
-	results, err := ins.Unpack("exists", bz)
-	if err != nil {
-		return false, err
-	}
-
-	return results[0].(bool), nil
+	return callResult[bool](ctx, cli, erc721ExistsABI, token, "exists", blockNumber, tokenId)
 }
 
 func ERC721SupportsInterface(ctx context.Context, cli *ethclient.Client, token string, blockNumber *big.Int) (bool, error) {
-	ins, err := abi.JSON(strings.NewReader(customERC721SupportsInterface))
-	if err != nil {
-		return false, err
-	}
-	const _InterfaceId_ERC721 = "0x80ac58cd"
-	interfaceIdBz, _ := hexutil.Decode(_InterfaceId_ERC721)
-	var bs [4]byte
-	copy(bs[:], interfaceIdBz)
-	data, err := ins.Pack("supportsInterface", bs)
-	if err != nil {
-		return false, err
-	}
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return false, err
-	}
-
-	results, err := ins.Unpack("supportsInterface", bz)
-	if err != nil {
-		return false, err
-	}
-
-	return results[0].(bool), nil
+	return SupportsInterface(ctx, cli, token, InterfaceIdERC721, blockNumber)
 }
 
 func ERC721SafeTransferFromData(from, to string, tokenId *big.Int) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC721Abi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("safeTransferFrom", common.HexToAddress(from), common.HexToAddress(to), tokenId)
+	return erc721ABI.Pack("safeTransferFrom", common.HexToAddress(from), common.HexToAddress(to), tokenId)
 }
 
 func ERC721TransferFromData(from, to string, tokenId *big.Int) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC721Abi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("transferFrom", common.HexToAddress(from), common.HexToAddress(to), tokenId)
+	return erc721ABI.Pack("transferFrom", common.HexToAddress(from), common.HexToAddress(to), tokenId)
 }
 
 func ERC721ApproveData(to string, tokenId *big.Int) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC721Abi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("approve", common.HexToAddress(to), tokenId)
+	return erc721ABI.Pack("approve", common.HexToAddress(to), tokenId)
 }
 
 func ERC721SetApprovalForAllData(operator string, approved bool) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC721Abi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("setApprovalForAll", common.HexToAddress(operator), approved)
+	return erc721ABI.Pack("setApprovalForAll", common.HexToAddress(operator), approved)
 }
 
 func ERC721SafeTransferFromWithDataData(from, to string, tokenId *big.Int, calldata []byte) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinIERC721Abi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("safeTransferFrom", common.HexToAddress(from), common.HexToAddress(to), tokenId, calldata)
+	return erc721ABI.Pack("safeTransferFrom", common.HexToAddress(from), common.HexToAddress(to), tokenId, calldata)
 }
 
 func ERC721MintData(to string) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(customERC721Mint))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("mint", common.HexToAddress(to))
+	return erc721MintABI.Pack("mint", common.HexToAddress(to))
 }
 
 func ERC721MintWithTokenURIData(to string, uri string) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(customERC721MintWithURI))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("mint", common.HexToAddress(to), uri)
+	return erc721MintWithURIABI.Pack("mint", common.HexToAddress(to), uri)
 }
 
 func ERC721MintWithTokenIdAndURIData(to string, tokenId *big.Int, uri string) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(customERC721MintWithTokenIdAndURI))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("mint", common.HexToAddress(to), tokenId, uri)
+	return erc721MintWithTokenIdAndURIABI.Pack("mint", common.HexToAddress(to), tokenId, uri)
 }
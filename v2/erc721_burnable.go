@@ -3,9 +3,7 @@ package ethcli
 import (
 	"context"
 	"math/big"
-	"strings"
 
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
@@ -14,18 +12,9 @@ var (
 )
 
 func ERC721Burn(ctx context.Context, cli *ethclient.Client, token string, key string, tokenId *big.Int) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinERC721BurnableAbi))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("burn", tokenId)
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(data), "0", 0)
+	return sendContract(ctx, cli, key, erc721BurnableABI, token, "burn", tokenId)
 }
 
 func ERC721BurnData(tokenId *big.Int) ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinERC721BurnableAbi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("burn", tokenId)
+	return erc721BurnableABI.Pack("burn", tokenId)
 }
@@ -3,10 +3,7 @@ package ethcli
 import (
 	"context"
 	"math/big"
-	"strings"
 
-	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
@@ -16,73 +13,13 @@ var (
 )
 
 func ERC721TokenOfOwnerByIndex(ctx context.Context, cli *ethclient.Client, token string, owner string, index *big.Int, blockNumber *big.Int) (*big.Int, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinERC721EnumerableAbi))
-	if err != nil {
-		return nil, err
-	}
-	data, _ := ins.Pack("tokenOfOwnerByIndex", common.HexToAddress(owner), index)
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return nil, err
-	}
-
-	results, err := ins.Unpack("tokenOfOwnerByIndex", bz)
-	if err != nil {
-		return nil, err
-	}
-
-	return results[0].(*big.Int), nil
+	return callResult[*big.Int](ctx, cli, erc721EnumerableABI, token, "tokenOfOwnerByIndex", blockNumber, common.HexToAddress(owner), index)
 }
 
 func ERC721TotalSupply(ctx context.Context, cli *ethclient.Client, token string, blockNumber *big.Int) (*big.Int, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinERC721EnumerableAbi))
-	if err != nil {
-		return nil, err
-	}
-	data, _ := ins.Pack("totalSupply")
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return nil, err
-	}
-
-	results, err := ins.Unpack("totalSupply", bz)
-	if err != nil {
-		return nil, err
-	}
-
-	return results[0].(*big.Int), nil
+	return callResult[*big.Int](ctx, cli, erc721EnumerableABI, token, "totalSupply", blockNumber)
 }
 
 func ERC721TokenByIndex(ctx context.Context, cli *ethclient.Client, token string, index *big.Int, blockNumber *big.Int) (*big.Int, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinERC721EnumerableAbi))
-	if err != nil {
-		return nil, err
-	}
-	data, _ := ins.Pack("tokenByIndex", index)
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return nil, err
-	}
-
-	results, err := ins.Unpack("tokenByIndex", bz)
-	if err != nil {
-		return nil, err
-	}
-
-	return results[0].(*big.Int), nil
+	return callResult[*big.Int](ctx, cli, erc721EnumerableABI, token, "tokenByIndex", blockNumber, index)
 }
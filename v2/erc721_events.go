@@ -0,0 +1,162 @@
+package ethcli
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ERC721TransferEvent is a decoded ERC721 Transfer(from, to, tokenId) log.
+type ERC721TransferEvent struct {
+	Token       common.Address
+	From        common.Address
+	To          common.Address
+	TokenId     *big.Int
+	Kind        TransferKind
+	BlockNumber uint64
+	TxHash      common.Hash
+	LogIndex    uint
+	Removed     bool
+}
+
+// ERC721ApprovalEvent is a decoded ERC721 Approval(owner, approved, tokenId) log.
+type ERC721ApprovalEvent struct {
+	Token       common.Address
+	Owner       common.Address
+	Approved    common.Address
+	TokenId     *big.Int
+	BlockNumber uint64
+	TxHash      common.Hash
+	LogIndex    uint
+	Removed     bool
+}
+
+// ERC721ApprovalForAllEvent is a decoded ApprovalForAll(owner, operator, approved) log.
+type ERC721ApprovalForAllEvent struct {
+	Token       common.Address
+	Owner       common.Address
+	Operator    common.Address
+	Approved    bool
+	BlockNumber uint64
+	TxHash      common.Hash
+	LogIndex    uint
+	Removed     bool
+}
+
+// FilterERC721Transfers returns the Transfer events of tokens in [fromBlock, toBlock].
+// Empty filters match any value; a nil toBlock means latest.
+func FilterERC721Transfers(ctx context.Context, cli *ethclient.Client, tokens, from, to []string, tokenIds []*big.Int, fromBlock, toBlock *big.Int) ([]ERC721TransferEvent, error) {
+	return filterEvents(ctx, cli, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: toAddresses(tokens),
+		Topics:    [][]common.Hash{{erc721ABI.Events["Transfer"].ID}, addressTopics(from), addressTopics(to), bigTopics(tokenIds)},
+	}, decodeERC721Transfer)
+}
+
+// FilterERC721Approvals returns the Approval events of tokens in [fromBlock, toBlock].
+func FilterERC721Approvals(ctx context.Context, cli *ethclient.Client, tokens, owners, approved []string, tokenIds []*big.Int, fromBlock, toBlock *big.Int) ([]ERC721ApprovalEvent, error) {
+	return filterEvents(ctx, cli, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: toAddresses(tokens),
+		Topics:    [][]common.Hash{{erc721ABI.Events["Approval"].ID}, addressTopics(owners), addressTopics(approved), bigTopics(tokenIds)},
+	}, decodeERC721Approval)
+}
+
+// FilterERC721ApprovalForAll returns the ApprovalForAll events of tokens in [fromBlock, toBlock].
+func FilterERC721ApprovalForAll(ctx context.Context, cli *ethclient.Client, tokens, owners, operators []string, fromBlock, toBlock *big.Int) ([]ERC721ApprovalForAllEvent, error) {
+	return filterEvents(ctx, cli, ethereum.FilterQuery{
+		FromBlock: fromBlock,
+		ToBlock:   toBlock,
+		Addresses: toAddresses(tokens),
+		Topics:    [][]common.Hash{{erc721ABI.Events["ApprovalForAll"].ID}, addressTopics(owners), addressTopics(operators)},
+	}, decodeERC721ApprovalForAll)
+}
+
+// WatchERC721Transfers streams new Transfer events of tokens into sink. It needs a websocket or IPC client.
+func WatchERC721Transfers(ctx context.Context, cli *ethclient.Client, tokens, from, to []string, tokenIds []*big.Int, sink chan<- ERC721TransferEvent) (ethereum.Subscription, error) {
+	return watchEvents(ctx, cli, ethereum.FilterQuery{
+		Addresses: toAddresses(tokens),
+		Topics:    [][]common.Hash{{erc721ABI.Events["Transfer"].ID}, addressTopics(from), addressTopics(to), bigTopics(tokenIds)},
+	}, decodeERC721Transfer, sink)
+}
+
+// WatchERC721Approvals streams new Approval events of tokens into sink. It needs a websocket or IPC client.
+func WatchERC721Approvals(ctx context.Context, cli *ethclient.Client, tokens, owners, approved []string, tokenIds []*big.Int, sink chan<- ERC721ApprovalEvent) (ethereum.Subscription, error) {
+	return watchEvents(ctx, cli, ethereum.FilterQuery{
+		Addresses: toAddresses(tokens),
+		Topics:    [][]common.Hash{{erc721ABI.Events["Approval"].ID}, addressTopics(owners), addressTopics(approved), bigTopics(tokenIds)},
+	}, decodeERC721Approval, sink)
+}
+
+// WatchERC721ApprovalForAll streams new ApprovalForAll events of tokens into sink. It needs a websocket or IPC client.
+func WatchERC721ApprovalForAll(ctx context.Context, cli *ethclient.Client, tokens, owners, operators []string, sink chan<- ERC721ApprovalForAllEvent) (ethereum.Subscription, error) {
+	return watchEvents(ctx, cli, ethereum.FilterQuery{
+		Addresses: toAddresses(tokens),
+		Topics:    [][]common.Hash{{erc721ABI.Events["ApprovalForAll"].ID}, addressTopics(owners), addressTopics(operators)},
+	}, decodeERC721ApprovalForAll, sink)
+}
+
+// decodeERC721Transfer decodes a Transfer log, skipping the ERC20 variant that does not index the third argument.
+func decodeERC721Transfer(log types.Log) ([]ERC721TransferEvent, error) {
+	if len(log.Topics) != 4 || log.Topics[0] != erc721ABI.Events["Transfer"].ID {
+		return nil, nil
+	}
+	from, to := HashToAddress(log.Topics[1]), HashToAddress(log.Topics[2])
+	return []ERC721TransferEvent{{
+		Token:       log.Address,
+		From:        from,
+		To:          to,
+		TokenId:     HashToBigInt(log.Topics[3]),
+		Kind:        ClassifyTransfer(from, to),
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+		Removed:     log.Removed,
+	}}, nil
+}
+
+func decodeERC721Approval(log types.Log) ([]ERC721ApprovalEvent, error) {
+	if len(log.Topics) != 4 || log.Topics[0] != erc721ABI.Events["Approval"].ID {
+		return nil, nil
+	}
+	return []ERC721ApprovalEvent{{
+		Token:       log.Address,
+		Owner:       HashToAddress(log.Topics[1]),
+		Approved:    HashToAddress(log.Topics[2]),
+		TokenId:     HashToBigInt(log.Topics[3]),
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+		Removed:     log.Removed,
+	}}, nil
+}
+
+func decodeERC721ApprovalForAll(log types.Log) ([]ERC721ApprovalForAllEvent, error) {
+	if len(log.Topics) != 3 || log.Topics[0] != erc721ABI.Events["ApprovalForAll"].ID {
+		return nil, nil
+	}
+	results, err := unpackLog(erc721ABI, "ApprovalForAll", log)
+	if err != nil {
+		return nil, err
+	}
+	approved, err := resultAt[bool]("ApprovalForAll", results, 0)
+	if err != nil {
+		return nil, err
+	}
+	return []ERC721ApprovalForAllEvent{{
+		Token:       log.Address,
+		Owner:       HashToAddress(log.Topics[1]),
+		Operator:    HashToAddress(log.Topics[2]),
+		Approved:    approved,
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+		Removed:     log.Removed,
+	}}, nil
+}
@@ -3,11 +3,7 @@ package ethcli
 import (
 	"context"
 	"math/big"
-	"strings"
 
-	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
@@ -16,51 +12,11 @@ var (
 )
 
 func ERC721Name(ctx context.Context, cli *ethclient.Client, token string, blockNumber *big.Int) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinERC721MetadataAbi))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("name")
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	results, err := ins.Unpack("name", bz)
-	if err != nil {
-		return "", err
-	}
-
-	return results[0].(string), nil
+	return callResult[string](ctx, cli, erc721MetadataABI, token, "name", blockNumber)
 }
 
 func ERC721Symbol(ctx context.Context, cli *ethclient.Client, token string, blockNumber *big.Int) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinERC721MetadataAbi))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("symbol")
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return "", err
-	}
-
-	results, err := ins.Unpack("symbol", bz)
-	if err != nil {
-		return "", err
-	}
-
-	return results[0].(string), nil
+	return callResult[string](ctx, cli, erc721MetadataABI, token, "symbol", blockNumber)
 }
 
 // ERC721TokenURI
@@ -0,0 +1,135 @@
+package ethcli
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ERC721OwnedTokens returns the ids of the tokens owned by owner at blockNumber, in ascending order.
+// Enumerable collections are read with tokenOfOwnerByIndex unless the balance is above
+// maxEnumeratedTokens. Otherwise every token ever transferred to owner since fromBlock (the
+// deployment block, nil for genesis) is collected from Transfer logs and kept if a batched
+// ownerOf still returns owner.
+func ERC721OwnedTokens(ctx context.Context, cli *ethclient.Client, token, owner string, fromBlock, blockNumber *big.Int) ([]*big.Int, error) {
+	// a revert means the contract has no ERC165 and is handled as not enumerable
+	enumerable, err := SupportsInterface(ctx, cli, token, InterfaceIdERC721Enumerable, blockNumber)
+	if err := ignoreCallFailure(err); err != nil {
+		return nil, err
+	}
+	if enumerable {
+		balance, err := ERC721BalanceOf(ctx, cli, token, owner, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		// a bogus or huge balance is not worth one call per index, the logs bound the work instead
+		if balance.IsInt64() && balance.Int64() <= maxEnumeratedTokens {
+			return erc721EnumerateOwner(ctx, cli, token, owner, balance.Int64(), blockNumber)
+		}
+	}
+	return erc721ScanOwner(ctx, cli, token, owner, fromBlock, blockNumber)
+}
+
+// maxEnumeratedTokens caps the balance ERC721OwnedTokens reads with tokenOfOwnerByIndex.
+const maxEnumeratedTokens = 10000
+
+func erc721EnumerateOwner(ctx context.Context, cli *ethclient.Client, token, owner string, balance int64, blockNumber *big.Int) ([]*big.Int, error) {
+	contract := common.HexToAddress(token)
+	msgs := make([]ethereum.CallMsg, 0, balance)
+	for i := int64(0); i < balance; i++ {
+		data, err := erc721EnumerableABI.Pack("tokenOfOwnerByIndex", common.HexToAddress(owner), big.NewInt(i))
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, ethereum.CallMsg{To: &contract, Data: data})
+	}
+	results, err := BatchCallContract(ctx, cli, msgs, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]*big.Int, 0, len(results))
+	for _, r := range results {
+		if r.Err != nil {
+			return nil, r.Err
+		}
+		values, err := erc721EnumerableABI.Unpack("tokenOfOwnerByIndex", r.Data)
+		if err != nil {
+			return nil, err
+		}
+		id, err := resultAt[*big.Int]("tokenOfOwnerByIndex", values, 0)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Cmp(ids[j]) < 0 })
+	return ids, nil
+}
+
+func erc721ScanOwner(ctx context.Context, cli *ethclient.Client, token, owner string, fromBlock, blockNumber *big.Int) ([]*big.Int, error) {
+	scanner := NewLogScanner(cli, ethereum.FilterQuery{
+		Addresses: []common.Address{common.HexToAddress(token)},
+		Topics:    [][]common.Hash{{erc721ABI.Events["Transfer"].ID}, nil, addressTopics([]string{owner})},
+	})
+	seen := map[string]bool{}
+	var candidates []*big.Int
+	err := scanner.Scan(ctx, fromBlock, blockNumber, func(from, to uint64, logs []types.Log) error {
+		for _, log := range logs {
+			events, err := decodeERC721Transfer(log)
+			if err != nil {
+				return err
+			}
+			for _, ev := range events {
+				if !seen[ev.TokenId.String()] {
+					seen[ev.TokenId.String()] = true
+					candidates = append(candidates, ev.TokenId)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	contract := common.HexToAddress(token)
+	msgs := make([]ethereum.CallMsg, 0, len(candidates))
+	for _, id := range candidates {
+		data, err := erc721ABI.Pack("ownerOf", id)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, ethereum.CallMsg{To: &contract, Data: data})
+	}
+	results, err := BatchCallContract(ctx, cli, msgs, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	account := common.HexToAddress(owner)
+	var ids []*big.Int
+	for i, r := range results {
+		if r.Err != nil {
+			// burned tokens revert
+			if isCallFailure(r.Err) {
+				continue
+			}
+			return nil, r.Err
+		}
+		values, err := erc721ABI.Unpack("ownerOf", r.Data)
+		if err != nil {
+			continue
+		}
+		if holder, err := resultAt[common.Address]("ownerOf", values, 0); err == nil && holder == account {
+			ids = append(ids, candidates[i])
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].Cmp(ids[j]) < 0 })
+	return ids, nil
+}
@@ -0,0 +1,142 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func Test_ERC721OwnedTokens(t *testing.T) {
+	other := common.HexToAddress("0x5555555555555555555555555555555555555555")
+	owners := map[int64]common.Address{1: exampleAddress, 2: other, 4: exampleAddress}
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_call": mockContracts([]string{customERC721SupportsInterface, openzeppelinIERC721Abi}, map[string]mockMethod{
+			"supportsInterface": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{false}, nil
+			},
+			"ownerOf": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				owner, ok := owners[args[0].(*big.Int).Int64()]
+				if !ok {
+					return nil, mockRevert
+				}
+				return []interface{}{owner}, nil
+			},
+		}),
+		"eth_getLogs": func(params []json.RawMessage) (interface{}, *mockError) {
+			var logs []types.Log
+			// token 1 arrived twice, 2 was sold, 3 was burned
+			for i, id := range []int64{1, 2, 3, 1, 4} {
+				logs = append(logs, types.Log{
+					Address:     exampleToken,
+					Topics:      []common.Hash{transferTopic, {}, addressTopic(exampleAddress), common.BigToHash(big.NewInt(id))},
+					BlockNumber: uint64(i),
+				})
+			}
+			return logs, nil
+		},
+	})
+
+	ids, err := ERC721OwnedTokens(context.Background(), mock.client(t), exampleToken.Hex(), exampleAddress.Hex(), nil, big.NewInt(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[1 4]" {
+		t.Fatalf("unexpected tokens %v", ids)
+	}
+}
+
+func Test_ERC721OwnedTokensCallErrors(t *testing.T) {
+	limited := &mockError{Code: -32005, Message: "rate limit exceeded"}
+	for _, failing := range []string{"supportsInterface", "ownerOf"} {
+		mock := newMockRPC(t, map[string]mockHandler{
+			"eth_call": mockContracts([]string{customERC721SupportsInterface, openzeppelinIERC721Abi}, map[string]mockMethod{
+				"supportsInterface": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+					if failing == "supportsInterface" {
+						return nil, limited
+					}
+					return nil, mockRevert
+				},
+				"ownerOf": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+					return nil, limited
+				},
+			}),
+			"eth_getLogs": func(params []json.RawMessage) (interface{}, *mockError) {
+				return []types.Log{{
+					Address: exampleToken,
+					Topics:  []common.Hash{transferTopic, {}, addressTopic(exampleAddress), common.BigToHash(big.NewInt(1))},
+				}}, nil
+			},
+		})
+		_, err := ERC721OwnedTokens(context.Background(), mock.client(t), exampleToken.Hex(), exampleAddress.Hex(), nil, big.NewInt(100))
+		if err == nil || !strings.Contains(err.Error(), limited.Message) {
+			t.Fatalf("%s: expected the rate limit error, got %v", failing, err)
+		}
+	}
+}
+
+func Test_ERC721OwnedTokensEnumerable(t *testing.T) {
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_call": mockContracts([]string{customERC721SupportsInterface, openzeppelinIERC721Abi, openzeppelinERC721EnumerableAbi}, map[string]mockMethod{
+			"supportsInterface": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{args[0].([4]byte) == InterfaceIdERC721Enumerable}, nil
+			},
+			"balanceOf": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{big.NewInt(3)}, nil
+			},
+			"tokenOfOwnerByIndex": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{new(big.Int).Sub(big.NewInt(30), args[1].(*big.Int))}, nil
+			},
+		}),
+	})
+
+	ids, err := ERC721OwnedTokens(context.Background(), mock.client(t), exampleToken.Hex(), exampleAddress.Hex(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[28 29 30]" {
+		t.Fatalf("unexpected tokens %v", ids)
+	}
+	if mock.count("eth_getLogs") != 0 {
+		t.Fatal("enumerable collection should not scan logs")
+	}
+}
+
+func Test_ERC721OwnedTokensOversizedBalance(t *testing.T) {
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_call": mockContracts([]string{customERC721SupportsInterface, openzeppelinIERC721Abi, openzeppelinERC721EnumerableAbi}, map[string]mockMethod{
+			"supportsInterface": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{args[0].([4]byte) == InterfaceIdERC721Enumerable}, nil
+			},
+			"balanceOf": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{new(big.Int).Lsh(big.NewInt(1), 255)}, nil
+			},
+			"tokenOfOwnerByIndex": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				t.Fatal("an oversized balance must not be enumerated")
+				return nil, nil
+			},
+			"ownerOf": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{exampleAddress}, nil
+			},
+		}),
+		"eth_getLogs": func(params []json.RawMessage) (interface{}, *mockError) {
+			return []types.Log{{
+				Address: exampleToken,
+				Topics:  []common.Hash{transferTopic, {}, addressTopic(exampleAddress), common.BigToHash(big.NewInt(7))},
+			}}, nil
+		},
+	})
+
+	ids, err := ERC721OwnedTokens(context.Background(), mock.client(t), exampleToken.Hex(), exampleAddress.Hex(), big.NewInt(0), big.NewInt(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ids) != "[7]" {
+		t.Fatalf("unexpected tokens %v", ids)
+	}
+}
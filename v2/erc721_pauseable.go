@@ -3,11 +3,7 @@ package ethcli
 import (
 	"context"
 	"math/big"
-	"strings"
 
-	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
@@ -16,59 +12,21 @@ var (
 )
 
 func ERC721Pause(ctx context.Context, cli *ethclient.Client, token string, key string) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinERC721PauseableAbi))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("pause")
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(data), "0", 0)
+	return sendContract(ctx, cli, key, erc721PausableABI, token, "pause")
 }
 
 func ERC721Unpause(ctx context.Context, cli *ethclient.Client, token string, key string) (string, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinERC721PauseableAbi))
-	if err != nil {
-		return "", err
-	}
-	data, _ := ins.Pack("unpause")
-	return SendLegacyTx(ctx, cli, key, &token, "0", BytesToHex(data), "0", 0)
+	return sendContract(ctx, cli, key, erc721PausableABI, token, "unpause")
 }
 
 func ERC721Paused(ctx context.Context, cli *ethclient.Client, token string, blockNumber *big.Int) (bool, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinERC721PauseableAbi))
-	if err != nil {
-		return false, err
-	}
-	data, _ := ins.Pack("paused")
-
-	contract := common.HexToAddress(token)
-	bz, err := cli.CallContract(ctx, ethereum.CallMsg{
-		To:   &contract,
-		Data: data,
-	}, blockNumber)
-	if err != nil {
-		return false, err
-	}
-
-	results, err := ins.Unpack("paused", bz)
-	if err != nil {
-		return false, err
-	}
-
-	return results[0].(bool), nil
+	return callResult[bool](ctx, cli, erc721PausableABI, token, "paused", blockNumber)
 }
 
 func ERC721PauseData() ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinERC721PauseableAbi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("pause")
+	return erc721PausableABI.Pack("pause")
 }
 
 func ERC721UnpauseData() ([]byte, error) {
-	ins, err := abi.JSON(strings.NewReader(openzeppelinERC721PauseableAbi))
-	if err != nil {
-		return nil, err
-	}
-	return ins.Pack("unpause")
+	return erc721PausableABI.Pack("unpause")
 }
@@ -0,0 +1,135 @@
+package ethcli
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// TransferKind classifies a token transfer by its zero-address endpoints.
+type TransferKind uint8
+
+const (
+	TransferKindTransfer TransferKind = iota
+	TransferKindMint
+	TransferKindBurn
+)
+
+func (k TransferKind) String() string {
+	switch k {
+	case TransferKindMint:
+		return "mint"
+	case TransferKindBurn:
+		return "burn"
+	default:
+		return "transfer"
+	}
+}
+
+// ClassifyTransfer reports a transfer from the zero address as a mint and one to the zero address as a burn.
+func ClassifyTransfer(from, to common.Address) TransferKind {
+	switch {
+	case from == (common.Address{}):
+		return TransferKindMint
+	case to == (common.Address{}):
+		return TransferKindBurn
+	default:
+		return TransferKindTransfer
+	}
+}
+
+// filterEvents runs query and decodes every returned log. Logs of another event are dropped;
+// it fails on the first log that matches the event but does not decode.
+func filterEvents[T any](ctx context.Context, cli *ethclient.Client, query ethereum.FilterQuery, decode func(types.Log) ([]T, error)) ([]T, error) {
+	logs, err := cli.FilterLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]T, 0, len(logs))
+	for _, v := range logs {
+		decoded, err := decode(v)
+		if err != nil {
+			return nil, fmt.Errorf("log %d of tx %s: %w", v.Index, v.TxHash.Hex(), err)
+		}
+		events = append(events, decoded...)
+	}
+	return events, nil
+}
+
+// watchEvents subscribes to query and forwards decoded events to sink until unsubscribed.
+// A log that does not decode ends the subscription with its error.
+func watchEvents[T any](ctx context.Context, cli *ethclient.Client, query ethereum.FilterQuery, decode func(types.Log) ([]T, error), sink chan<- T) (ethereum.Subscription, error) {
+	logs := make(chan types.Log)
+	sub, err := cli.SubscribeFilterLogs(ctx, query, logs)
+	if err != nil {
+		return nil, err
+	}
+
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case v := <-logs:
+				decoded, err := decode(v)
+				if err != nil {
+					return fmt.Errorf("log %d of tx %s: %w", v.Index, v.TxHash.Hex(), err)
+				}
+				for _, ev := range decoded {
+					select {
+					case sink <- ev:
+					case err := <-sub.Err():
+						return err
+					case <-quit:
+						return nil
+					}
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// unpackLog unpacks the data of an event log, reporting malformed data as ErrUnexpectedResult.
+func unpackLog(ins *abi.ABI, event string, log types.Log) ([]interface{}, error) {
+	results, err := ins.Unpack(event, log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrUnexpectedResult, event, err)
+	}
+	return results, nil
+}
+
+func toAddresses(addrs []string) []common.Address {
+	var out []common.Address
+	for _, v := range addrs {
+		out = append(out, common.HexToAddress(v))
+	}
+	return out
+}
+
+// addressTopics converts addresses into indexed-argument topics; nil matches any value.
+func addressTopics(addrs []string) []common.Hash {
+	var out []common.Hash
+	for _, v := range addrs {
+		out = append(out, common.BytesToHash(common.HexToAddress(v).Bytes()))
+	}
+	return out
+}
+
+// bigTopics converts uint256 values into indexed-argument topics; nil matches any value.
+func bigTopics(values []*big.Int) []common.Hash {
+	var out []common.Hash
+	for _, v := range values {
+		out = append(out, common.BigToHash(v))
+	}
+	return out
+}
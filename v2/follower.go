@@ -0,0 +1,269 @@
+package ethcli
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// ErrReorgTooDeep is returned when the common ancestor of a reorg lies beyond the tracked blocks.
+var ErrReorgTooDeep = errors.New("reorg deeper than tracked blocks")
+
+type ChainEventType int
+
+const (
+	ChainApply ChainEventType = iota
+	ChainRevert
+)
+
+func (t ChainEventType) String() string {
+	switch t {
+	case ChainApply:
+		return "apply"
+	case ChainRevert:
+		return "revert"
+	}
+	return "unknown"
+}
+
+// ChainEvent is a block joining or leaving the canonical chain. Logs holds the block's logs
+// matching the follower's query; for reverted blocks they are marked Removed.
+type ChainEvent struct {
+	Type   ChainEventType
+	Header *types.Header
+	Logs   []types.Log
+}
+
+type trackedBlock struct {
+	header *types.Header
+	logs   []types.Log
+}
+
+// ChainFollower follows the chain head and reports every change as ordered ChainEvents. When a
+// new head does not extend the followed chain, the blocks above the common ancestor are reverted
+// newest first before the new branch is applied oldest first.
+type ChainFollower struct {
+	Client       *ethclient.Client
+	Query        *ethereum.FilterQuery // Addresses and Topics of the logs to fetch per block, nil for headers only
+	Depth        int                   // number of recent blocks kept for reorg detection
+	PollInterval time.Duration         // used when the connection has no subscriptions, e.g. HTTP
+
+	recent []trackedBlock
+}
+
+// ChainHandler receives the events of a ChainFollower.
+type ChainHandler func(ev ChainEvent) error
+
+func NewChainFollower(cli *ethclient.Client, query *ethereum.FilterQuery) *ChainFollower {
+	return &ChainFollower{
+		Client:       cli,
+		Query:        query,
+		Depth:        128,
+		PollInterval: 4 * time.Second,
+	}
+}
+
+// Run follows the chain starting at fromBlock (nil for the current head) until ctx is done or
+// handle fails. New heads come from eth_subscribe when the connection supports it, otherwise
+// the head is polled every PollInterval.
+func (f *ChainFollower) Run(ctx context.Context, fromBlock *big.Int, handle ChainHandler) error {
+	if len(f.recent) == 0 {
+		header, err := f.Client.HeaderByNumber(ctx, fromBlock)
+		if err != nil {
+			return err
+		}
+		if err := f.apply(ctx, header, handle); err != nil {
+			return err
+		}
+	}
+
+	heads := make(chan *types.Header, 16)
+	sub, err := f.Client.SubscribeNewHead(ctx, heads)
+	if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+		return f.poll(ctx, handle)
+	}
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	// catch up before waiting for the next head
+	if err := f.pollOnce(ctx, handle); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return err
+		case head := <-heads:
+			if err := f.Advance(ctx, head, handle); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (f *ChainFollower) poll(ctx context.Context, handle ChainHandler) error {
+	interval := f.PollInterval
+	if interval <= 0 {
+		interval = 4 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := f.pollOnce(ctx, handle); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *ChainFollower) pollOnce(ctx context.Context, handle ChainHandler) error {
+	head, err := f.Client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	return f.Advance(ctx, head, handle)
+}
+
+// Head returns the latest applied header, or nil before the first block.
+func (f *ChainFollower) Head() *types.Header {
+	if len(f.recent) == 0 {
+		return nil
+	}
+	return f.recent[len(f.recent)-1].header
+}
+
+// Advance moves the follower to head, reverting and applying blocks as needed. A head that is
+// already part of the followed chain is ignored, so a lagging node can't roll it back.
+// Blocks more than Depth below head are fetched forward by number; only the last Depth blocks
+// are walked back by hash to find the common ancestor.
+func (f *ChainFollower) Advance(ctx context.Context, head *types.Header, handle ChainHandler) error {
+	if len(f.recent) == 0 {
+		return f.apply(ctx, head, handle)
+	}
+	window := new(big.Int).Sub(head.Number, big.NewInt(int64(f.depth())))
+	for f.Head().Number.Cmp(window) < 0 {
+		tail := f.Head()
+		header, err := f.Client.HeaderByNumber(ctx, new(big.Int).Add(tail.Number, big.NewInt(1)))
+		if err != nil {
+			return err
+		}
+		if header.ParentHash == tail.Hash() {
+			err = f.apply(ctx, header, handle)
+		} else {
+			err = f.switchTo(ctx, header, handle)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return f.switchTo(ctx, head, handle)
+}
+
+// switchTo makes head the followed head, walking back by hash to the common ancestor.
+func (f *ChainFollower) switchTo(ctx context.Context, head *types.Header, handle ChainHandler) error {
+	tail := f.Head()
+
+	// collect the new branch down to a block we already track
+	var added []*types.Header
+	h := head
+	for h.Number.Cmp(tail.Number) > 0 {
+		added = append(added, h)
+		parent, err := f.Client.HeaderByHash(ctx, h.ParentHash)
+		if err != nil {
+			return err
+		}
+		h = parent
+	}
+	ancestor := -1
+	for {
+		idx := f.indexOf(h.Number)
+		if idx < 0 {
+			return ErrReorgTooDeep
+		}
+		if f.recent[idx].header.Hash() == h.Hash() {
+			ancestor = idx
+			break
+		}
+		added = append(added, h)
+		parent, err := f.Client.HeaderByHash(ctx, h.ParentHash)
+		if err != nil {
+			return err
+		}
+		h = parent
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	for len(f.recent) > ancestor+1 {
+		last := f.recent[len(f.recent)-1]
+		logs := make([]types.Log, len(last.logs))
+		for i, l := range last.logs {
+			l.Removed = true
+			logs[len(logs)-1-i] = l
+		}
+		if err := handle(ChainEvent{Type: ChainRevert, Header: last.header, Logs: logs}); err != nil {
+			return err
+		}
+		f.recent = f.recent[:len(f.recent)-1]
+	}
+	for i := len(added) - 1; i >= 0; i-- {
+		if err := f.apply(ctx, added[i], handle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *ChainFollower) apply(ctx context.Context, header *types.Header, handle ChainHandler) error {
+	var logs []types.Log
+	if f.Query != nil {
+		hash := header.Hash()
+		query := *f.Query
+		query.FromBlock, query.ToBlock, query.BlockHash = nil, nil, &hash
+		var err error
+		if logs, err = f.Client.FilterLogs(ctx, query); err != nil {
+			return err
+		}
+	}
+	if err := handle(ChainEvent{Type: ChainApply, Header: header, Logs: logs}); err != nil {
+		return err
+	}
+	f.recent = append(f.recent, trackedBlock{header: header, logs: logs})
+	if depth := f.depth(); len(f.recent) > depth {
+		f.recent = f.recent[len(f.recent)-depth:]
+	}
+	return nil
+}
+
+func (f *ChainFollower) depth() int {
+	if f.Depth <= 0 {
+		return 128
+	}
+	return f.Depth
+}
+
+func (f *ChainFollower) indexOf(number *big.Int) int {
+	if len(f.recent) == 0 {
+		return -1
+	}
+	idx := new(big.Int).Sub(number, f.recent[0].header.Number)
+	if idx.Sign() < 0 || idx.Cmp(big.NewInt(int64(len(f.recent)))) >= 0 {
+		return -1
+	}
+	return int(idx.Int64())
+}
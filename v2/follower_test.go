@@ -0,0 +1,189 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// mockChain serves headers and one log per block for a canonical chain that tests can reorg.
+type mockChain struct {
+	mu        sync.Mutex
+	byHash    map[common.Hash]*types.Header
+	canonical []*types.Header
+}
+
+func newMockChain(length int) *mockChain {
+	c := &mockChain{byHash: map[common.Hash]*types.Header{}}
+	c.extend(length, "a")
+	return c
+}
+
+// extend appends blocks on branch until the chain has length blocks.
+func (c *mockChain) extend(length int, branch string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for n := len(c.canonical); n < length; n++ {
+		header := &types.Header{Number: big.NewInt(int64(n)), Difficulty: big.NewInt(1), Extra: []byte(branch)}
+		if n > 0 {
+			header.ParentHash = c.canonical[n-1].Hash()
+		}
+		c.byHash[header.Hash()] = header
+		c.canonical = append(c.canonical, header)
+	}
+}
+
+// reorg drops the blocks from number on and rebuilds the chain to length on a new branch.
+func (c *mockChain) reorg(number, length int, branch string) {
+	c.mu.Lock()
+	c.canonical = c.canonical[:number]
+	c.mu.Unlock()
+	c.extend(length, branch)
+}
+
+func (c *mockChain) rpc(t *testing.T) *mockRPC {
+	return newMockRPC(t, map[string]mockHandler{
+		"eth_getBlockByNumber": func(params []json.RawMessage) (interface{}, *mockError) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			var tag string
+			_ = json.Unmarshal(params[0], &tag)
+			if tag == "latest" {
+				return c.canonical[len(c.canonical)-1], nil
+			}
+			n, _ := hexutil.DecodeUint64(tag)
+			if n >= uint64(len(c.canonical)) {
+				return nil, nil
+			}
+			return c.canonical[n], nil
+		},
+		"eth_getBlockByHash": func(params []json.RawMessage) (interface{}, *mockError) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			var hash common.Hash
+			_ = json.Unmarshal(params[0], &hash)
+			return c.byHash[hash], nil
+		},
+		"eth_getLogs": func(params []json.RawMessage) (interface{}, *mockError) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			var q struct {
+				BlockHash common.Hash `json:"blockHash"`
+			}
+			_ = json.Unmarshal(params[0], &q)
+			header := c.byHash[q.BlockHash]
+			return []types.Log{{Address: exampleToken, Topics: []common.Hash{transferTopic}, BlockNumber: header.Number.Uint64(), BlockHash: q.BlockHash}}, nil
+		},
+	})
+}
+
+func eventString(ev ChainEvent) string {
+	return fmt.Sprintf("%s %d%s", ev.Type, ev.Header.Number, ev.Header.Extra)
+}
+
+func Test_ChainFollowerReorg(t *testing.T) {
+	chain := newMockChain(6)
+	follower := NewChainFollower(chain.rpc(t).client(t), &ethereum.FilterQuery{Addresses: []common.Address{exampleToken}})
+	follower.Depth = 4
+
+	var events []string
+	handle := func(ev ChainEvent) error {
+		events = append(events, eventString(ev))
+		if len(ev.Logs) != 1 || ev.Logs[0].BlockHash != ev.Header.Hash() || ev.Logs[0].Removed != (ev.Type == ChainRevert) {
+			t.Fatalf("unexpected logs for %s: %+v", eventString(ev), ev.Logs)
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+	header, _ := follower.Client.HeaderByNumber(ctx, big.NewInt(3))
+	if err := follower.Advance(ctx, header, handle); err != nil {
+		t.Fatal(err)
+	}
+	if err := follower.pollOnce(ctx, handle); err != nil {
+		t.Fatal(err)
+	}
+
+	chain.reorg(4, 7, "b")
+	if err := follower.pollOnce(ctx, handle); err != nil {
+		t.Fatal(err)
+	}
+	// a lagging node reporting an old head must not roll the follower back
+	stale, _ := follower.Client.HeaderByNumber(ctx, big.NewInt(5))
+	if err := follower.Advance(ctx, stale, handle); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"apply 3a", "apply 4a", "apply 5a",
+		"revert 5a", "revert 4a",
+		"apply 4b", "apply 5b", "apply 6b",
+	}
+	if fmt.Sprint(events) != fmt.Sprint(expected) {
+		t.Fatalf("unexpected events\n got %v\nwant %v", events, expected)
+	}
+	if follower.Head().Hash() != chain.canonical[6].Hash() {
+		t.Fatal("follower not at canonical head")
+	}
+
+	// only Depth blocks are kept, a reorg below them can't be handled
+	chain.reorg(2, 8, "c")
+	if err := follower.pollOnce(ctx, handle); err != ErrReorgTooDeep {
+		t.Fatalf("expected ErrReorgTooDeep, got %v", err)
+	}
+}
+
+func Test_ChainFollowerCatchUp(t *testing.T) {
+	chain := newMockChain(200)
+	mock := chain.rpc(t)
+	follower := NewChainFollower(mock.client(t), nil)
+	follower.Depth = 4
+
+	var applied []uint64
+	fail := errors.New("handler failed")
+	handle := func(ev ChainEvent) error {
+		if n := ev.Header.Number.Uint64(); n == 100 && fail != nil {
+			return fail
+		}
+		applied = append(applied, ev.Header.Number.Uint64())
+		return nil
+	}
+
+	ctx := context.Background()
+	genesis, _ := follower.Client.HeaderByNumber(ctx, big.NewInt(0))
+	if err := follower.Advance(ctx, genesis, handle); err != nil {
+		t.Fatal(err)
+	}
+	if err := follower.pollOnce(ctx, handle); !errors.Is(err, fail) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+	// a block whose handler failed is not tracked and is delivered again
+	if follower.Head().Number.Uint64() != 99 {
+		t.Fatalf("follower at %d after failed apply", follower.Head().Number)
+	}
+	fail = nil
+	if err := follower.pollOnce(ctx, handle); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(applied) != 200 {
+		t.Fatalf("applied %d blocks", len(applied))
+	}
+	for i, n := range applied {
+		if n != uint64(i) {
+			t.Fatalf("block %d applied at position %d", n, i)
+		}
+	}
+	if calls := mock.count("eth_getBlockByHash"); calls > 2*follower.Depth {
+		t.Fatalf("caught up by walking back %d headers", calls)
+	}
+}
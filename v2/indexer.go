@@ -0,0 +1,217 @@
+package ethcli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// ErrIndexerGap is returned when a block does not extend the indexer's head.
+var ErrIndexerGap = errors.New("block does not extend indexed head")
+
+// TransferIndexer maintains ERC20 balances, ERC721 owners and token counts, and ERC1155
+// balances of a set of contracts from their transfer logs. Every block is committed to the
+// store together with the previous values it overwrote, so reorged blocks can be reverted.
+// Balances are only exact when indexing starts at or before the contracts' deployment.
+type TransferIndexer struct {
+	Store  IndexerStore
+	tokens []common.Address
+}
+
+func NewTransferIndexer(store IndexerStore, tokens []string) *TransferIndexer {
+	return &TransferIndexer{Store: store, tokens: toAddresses(tokens)}
+}
+
+// Query returns the log filter selecting the transfer logs of the indexed contracts.
+func (x *TransferIndexer) Query() ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		Addresses: x.tokens,
+		Topics: [][]common.Hash{{
+			erc20ABI.Events["Transfer"].ID,
+			erc1155ABI.Events["TransferSingle"].ID,
+			erc1155ABI.Events["TransferBatch"].ID,
+		}},
+	}
+}
+
+// Run indexes the chain with a ChainFollower until ctx is done. An empty store starts at
+// fromBlock; otherwise indexing resumes at the stored head after reverting any stored blocks
+// that are no longer canonical. If the reorg reaches below the blocks the store can revert,
+// Run returns ErrReorgTooDeep.
+func (x *TransferIndexer) Run(ctx context.Context, cli *ethclient.Client, fromBlock *big.Int) error {
+	for {
+		head, err := x.Store.Head()
+		if err != nil {
+			return err
+		}
+		if head == nil {
+			break
+		}
+		header, err := cli.HeaderByNumber(ctx, new(big.Int).SetUint64(head.Number))
+		if err != nil {
+			return err
+		}
+		if header.Hash() == head.Hash {
+			fromBlock = header.Number
+			break
+		}
+		if _, err := x.Store.Rollback(); err != nil {
+			return fmt.Errorf("indexed block %d %s is not canonical: %w", head.Number, head.Hash.Hex(), err)
+		}
+	}
+	query := x.Query()
+	return NewChainFollower(cli, &query).Run(ctx, fromBlock, x.Handle)
+}
+
+// Handle is a ChainHandler applying and reverting blocks.
+func (x *TransferIndexer) Handle(ev ChainEvent) error {
+	switch ev.Type {
+	case ChainApply:
+		return x.ApplyBlock(ev.Header, ev.Logs)
+	case ChainRevert:
+		return x.RevertBlock(ev.Header)
+	}
+	return nil
+}
+
+// ApplyBlock indexes the transfer logs of a block. Applying the current head again is a no-op.
+func (x *TransferIndexer) ApplyBlock(header *types.Header, logs []types.Log) error {
+	head, err := x.Store.Head()
+	if err != nil {
+		return err
+	}
+	hash := header.Hash()
+	if head != nil {
+		if head.Hash == hash {
+			return nil
+		}
+		if header.ParentHash != head.Hash {
+			return fmt.Errorf("%w: block %d parent %s, head %d %s", ErrIndexerGap, header.Number, header.ParentHash.Hex(), head.Number, head.Hash.Hex())
+		}
+	}
+
+	b := &indexerBlock{
+		store:    x.Store,
+		block:    &IndexedBlock{Number: header.Number.Uint64(), Hash: hash, ParentHash: header.ParentHash},
+		balances: map[balanceKey]int{},
+		owners:   map[ownerKey]int{},
+	}
+	watched := make(map[common.Address]bool, len(x.tokens))
+	for _, token := range x.tokens {
+		watched[token] = true
+	}
+	for _, log := range logs {
+		if log.Removed || (len(watched) > 0 && !watched[log.Address]) {
+			continue
+		}
+		if err := x.applyLog(b, log); err != nil {
+			return err
+		}
+	}
+	return x.Store.Commit(b.block)
+}
+
+// RevertBlock restores the state before header, which has to be the indexed head.
+func (x *TransferIndexer) RevertBlock(header *types.Header) error {
+	head, err := x.Store.Head()
+	if err != nil {
+		return err
+	}
+	if head == nil || head.Hash != header.Hash() {
+		return fmt.Errorf("revert of block %d %s which is not the indexed head", header.Number, header.Hash().Hex())
+	}
+	_, err = x.Store.Rollback()
+	return err
+}
+
+func (x *TransferIndexer) applyLog(b *indexerBlock, log types.Log) error {
+	erc20, err := decodeERC20Transfer(log)
+	if err != nil {
+		return err
+	}
+	for _, ev := range erc20 {
+		if err := b.move(ev.Token, ev.From, ev.To, nil, ev.Value); err != nil {
+			return err
+		}
+	}
+	erc721, err := decodeERC721Transfer(log)
+	if err != nil {
+		return err
+	}
+	for _, ev := range erc721 {
+		if err := b.move(ev.Token, ev.From, ev.To, nil, big.NewInt(1)); err != nil {
+			return err
+		}
+		if err := b.setOwner(ev.Token, ev.TokenId, ev.To); err != nil {
+			return err
+		}
+	}
+	erc1155, err := decodeERC1155Transfer(log)
+	if err != nil {
+		return err
+	}
+	for _, ev := range flattenERC1155Transfers(erc1155) {
+		if err := b.move(ev.Token, ev.From, ev.To, ev.Ids[0], ev.Values[0]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexerBlock accumulates the changes of one block, one entry per balance or owner.
+type indexerBlock struct {
+	store    IndexerStore
+	block    *IndexedBlock
+	balances map[balanceKey]int
+	owners   map[ownerKey]int
+}
+
+func (b *indexerBlock) move(token, from, to common.Address, id, value *big.Int) error {
+	if from != (common.Address{}) {
+		if err := b.add(token, from, id, new(big.Int).Neg(value)); err != nil {
+			return err
+		}
+	}
+	if to != (common.Address{}) {
+		return b.add(token, to, id, value)
+	}
+	return nil
+}
+
+func (b *indexerBlock) add(token, holder common.Address, id, delta *big.Int) error {
+	key := balanceKey{token, holder, idKey(id)}
+	i, ok := b.balances[key]
+	if !ok {
+		old, err := b.store.Balance(token, holder, id)
+		if err != nil {
+			return err
+		}
+		i = len(b.block.Balances)
+		b.balances[key] = i
+		b.block.Balances = append(b.block.Balances, BalanceChange{Token: token, Holder: holder, Id: id, Old: old, New: new(big.Int).Set(old)})
+	}
+	b.block.Balances[i].New.Add(b.block.Balances[i].New, delta)
+	return nil
+}
+
+func (b *indexerBlock) setOwner(token common.Address, id *big.Int, owner common.Address) error {
+	key := ownerKey{token, idKey(id)}
+	i, ok := b.owners[key]
+	if !ok {
+		old, err := b.store.Owner(token, id)
+		if err != nil {
+			return err
+		}
+		i = len(b.block.Owners)
+		b.owners[key] = i
+		b.block.Owners = append(b.block.Owners, OwnerChange{Token: token, Id: id, Old: old})
+	}
+	b.block.Owners[i].New = owner
+	return nil
+}
@@ -0,0 +1,381 @@
+package ethcli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BalanceChange records a holder balance before and after a block. ERC20 balances and
+// ERC721 token counts have a nil Id.
+type BalanceChange struct {
+	Token  common.Address `json:"token"`
+	Holder common.Address `json:"holder"`
+	Id     *big.Int       `json:"id,omitempty"`
+	Old    *big.Int       `json:"old"`
+	New    *big.Int       `json:"new"`
+}
+
+// OwnerChange records the owner of an ERC721 token before and after a block.
+type OwnerChange struct {
+	Token common.Address `json:"token"`
+	Id    *big.Int       `json:"id"`
+	Old   common.Address `json:"old"`
+	New   common.Address `json:"new"`
+}
+
+// IndexedBlock is the set of changes a block made, kept so the block can be reverted.
+type IndexedBlock struct {
+	Number     uint64          `json:"number"`
+	Hash       common.Hash     `json:"hash"`
+	ParentHash common.Hash     `json:"parentHash"`
+	Balances   []BalanceChange `json:"balances"`
+	Owners     []OwnerChange   `json:"owners"`
+}
+
+// IndexerStore persists the state of a TransferIndexer.
+type IndexerStore interface {
+	// Balance returns the indexed balance, zero if unknown.
+	Balance(token, holder common.Address, id *big.Int) (*big.Int, error)
+	// Owner returns the indexed ERC721 owner, the zero address if unknown.
+	Owner(token common.Address, id *big.Int) (common.Address, error)
+	// Commit applies the New values of block and records it as the head.
+	Commit(block *IndexedBlock) error
+	// Rollback restores the Old values of the head block and returns it. It returns
+	// ErrReorgTooDeep once the head is older than the blocks kept for reverting.
+	Rollback() (*IndexedBlock, error)
+	// Head returns the block the indexed state belongs to, nil if nothing was committed.
+	// A head reached by rolling back past the kept blocks has only Number and Hash set.
+	Head() (*IndexedBlock, error)
+}
+
+type balanceKey struct {
+	token, holder common.Address
+	id            string
+}
+
+type ownerKey struct {
+	token common.Address
+	id    string
+}
+
+func idKey(id *big.Int) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+// MemoryIndexerStore keeps the indexed state in memory. Only the last Depth blocks can be rolled back.
+type MemoryIndexerStore struct {
+	Depth int
+
+	mu       sync.RWMutex
+	balances map[balanceKey]*big.Int
+	owners   map[ownerKey]common.Address
+	head     *IndexedBlock
+	journal  []*IndexedBlock
+}
+
+func NewMemoryIndexerStore() *MemoryIndexerStore {
+	return &MemoryIndexerStore{
+		Depth:    128,
+		balances: map[balanceKey]*big.Int{},
+		owners:   map[ownerKey]common.Address{},
+	}
+}
+
+func (s *MemoryIndexerStore) Balance(token, holder common.Address, id *big.Int) (*big.Int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if v, ok := s.balances[balanceKey{token, holder, idKey(id)}]; ok {
+		return new(big.Int).Set(v), nil
+	}
+	return new(big.Int), nil
+}
+
+func (s *MemoryIndexerStore) Owner(token common.Address, id *big.Int) (common.Address, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.owners[ownerKey{token, idKey(id)}], nil
+}
+
+func (s *MemoryIndexerStore) Commit(block *IndexedBlock) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, c := range block.Balances {
+		s.setBalance(c.Token, c.Holder, c.Id, c.New)
+	}
+	for _, c := range block.Owners {
+		s.setOwner(c.Token, c.Id, c.New)
+	}
+	s.head = block
+	s.journal = append(s.journal, block)
+	if s.Depth > 0 && len(s.journal) > s.Depth {
+		s.journal = s.journal[len(s.journal)-s.Depth:]
+	}
+	return nil
+}
+
+func (s *MemoryIndexerStore) Rollback() (*IndexedBlock, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.journal) == 0 {
+		return nil, ErrReorgTooDeep
+	}
+	block := s.journal[len(s.journal)-1]
+	for i := len(block.Balances) - 1; i >= 0; i-- {
+		c := block.Balances[i]
+		s.setBalance(c.Token, c.Holder, c.Id, c.Old)
+	}
+	for i := len(block.Owners) - 1; i >= 0; i-- {
+		c := block.Owners[i]
+		s.setOwner(c.Token, c.Id, c.Old)
+	}
+	s.journal = s.journal[:len(s.journal)-1]
+	switch {
+	case len(s.journal) > 0:
+		s.head = s.journal[len(s.journal)-1]
+	case block.Number > 0:
+		// the parent's changes are no longer kept, but the state still belongs to it
+		s.head = &IndexedBlock{Number: block.Number - 1, Hash: block.ParentHash}
+	default:
+		s.head = nil
+	}
+	return block, nil
+}
+
+func (s *MemoryIndexerStore) Head() (*IndexedBlock, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.head, nil
+}
+
+func (s *MemoryIndexerStore) setBalance(token, holder common.Address, id, value *big.Int) {
+	key := balanceKey{token, holder, idKey(id)}
+	if value == nil || value.Sign() == 0 {
+		delete(s.balances, key)
+		return
+	}
+	s.balances[key] = new(big.Int).Set(value)
+}
+
+func (s *MemoryIndexerStore) setOwner(token common.Address, id *big.Int, owner common.Address) {
+	key := ownerKey{token, idKey(id)}
+	if owner == (common.Address{}) {
+		delete(s.owners, key)
+		return
+	}
+	s.owners[key] = owner
+}
+
+type indexerBalanceEntry struct {
+	Token  common.Address `json:"token"`
+	Holder common.Address `json:"holder"`
+	Id     *big.Int       `json:"id,omitempty"`
+	Value  *big.Int       `json:"value"`
+}
+
+type indexerOwnerEntry struct {
+	Token common.Address `json:"token"`
+	Id    *big.Int       `json:"id"`
+	Owner common.Address `json:"owner"`
+}
+
+type indexerFileState struct {
+	Balances []indexerBalanceEntry `json:"balances"`
+	Owners   []indexerOwnerEntry   `json:"owners"`
+	Head     *IndexedBlock         `json:"head"`
+	Journal  []*IndexedBlock       `json:"journal"`
+}
+
+// indexerRecord is one line of a FileIndexerStore file.
+type indexerRecord struct {
+	State    *indexerFileState `json:"state,omitempty"`
+	Commit   *IndexedBlock     `json:"commit,omitempty"`
+	Rollback *common.Hash      `json:"rollback,omitempty"`
+}
+
+// FileIndexerStore is a MemoryIndexerStore that appends every committed or rolled back block to Path,
+// one JSON record per line, before the change is applied in memory. Past CompactAfter records the file
+// is rewritten as a single snapshot of the state, so reopening a store doesn't replay its whole history.
+type FileIndexerStore struct {
+	*MemoryIndexerStore
+	Path         string
+	CompactAfter int
+
+	records int
+}
+
+// NewFileIndexerStore opens the store at path, loading the state of a previous run if the file exists.
+// A record cut short by a crash is dropped.
+func NewFileIndexerStore(path string) (*FileIndexerStore, error) {
+	s := &FileIndexerStore{MemoryIndexerStore: NewMemoryIndexerStore(), Path: path, CompactAfter: 1024}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dec := json.NewDecoder(f)
+	var valid int64
+	for {
+		var record indexerRecord
+		err := dec.Decode(&record)
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			if err := os.Truncate(path, valid); err != nil {
+				return nil, err
+			}
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := s.replay(&record); err != nil {
+			return nil, err
+		}
+		valid = dec.InputOffset()
+		s.records++
+	}
+	return s, nil
+}
+
+func (s *FileIndexerStore) replay(record *indexerRecord) error {
+	switch {
+	case record.State != nil:
+		state := record.State
+		s.balances = map[balanceKey]*big.Int{}
+		s.owners = map[ownerKey]common.Address{}
+		for _, e := range state.Balances {
+			s.setBalance(e.Token, e.Holder, e.Id, e.Value)
+		}
+		for _, e := range state.Owners {
+			s.setOwner(e.Token, e.Id, e.Owner)
+		}
+		s.head, s.journal = state.Head, state.Journal
+	case record.Commit != nil:
+		return s.MemoryIndexerStore.Commit(record.Commit)
+	case record.Rollback != nil:
+		block, err := s.MemoryIndexerStore.Rollback()
+		if err != nil {
+			return err
+		}
+		if block.Hash != *record.Rollback {
+			return fmt.Errorf("%s: rollback of %s does not match head %s", s.Path, record.Rollback.Hex(), block.Hash.Hex())
+		}
+	}
+	return nil
+}
+
+// Commit writes block to the file before applying it, so a failed write leaves the store unchanged.
+func (s *FileIndexerStore) Commit(block *IndexedBlock) error {
+	if err := s.append(&indexerRecord{Commit: block}); err != nil {
+		return err
+	}
+	if err := s.MemoryIndexerStore.Commit(block); err != nil {
+		return err
+	}
+	return s.compactIfDue()
+}
+
+// Rollback writes the rollback of the head block to the file before restoring its Old values.
+func (s *FileIndexerStore) Rollback() (*IndexedBlock, error) {
+	s.mu.RLock()
+	if len(s.journal) == 0 {
+		s.mu.RUnlock()
+		return nil, ErrReorgTooDeep
+	}
+	hash := s.journal[len(s.journal)-1].Hash
+	s.mu.RUnlock()
+
+	if err := s.append(&indexerRecord{Rollback: &hash}); err != nil {
+		return nil, err
+	}
+	block, err := s.MemoryIndexerStore.Rollback()
+	if err != nil {
+		return nil, err
+	}
+	return block, s.compactIfDue()
+}
+
+// append writes record to the end of the file and syncs it.
+func (s *FileIndexerStore) append(record *indexerRecord) error {
+	bz, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(bz, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	s.records++
+	return f.Close()
+}
+
+// compactIfDue compacts the file once more than CompactAfter records were written.
+func (s *FileIndexerStore) compactIfDue() error {
+	if s.CompactAfter > 0 && s.records > s.CompactAfter {
+		return s.Compact()
+	}
+	return nil
+}
+
+// Compact rewrites the file as a single snapshot of the current state.
+func (s *FileIndexerStore) Compact() error {
+	s.mu.RLock()
+	state := indexerFileState{Head: s.head, Journal: s.journal}
+	for k, v := range s.balances {
+		id, _ := new(big.Int).SetString(k.id, 10)
+		state.Balances = append(state.Balances, indexerBalanceEntry{Token: k.token, Holder: k.holder, Id: id, Value: v})
+	}
+	for k, v := range s.owners {
+		id, _ := new(big.Int).SetString(k.id, 10)
+		state.Owners = append(state.Owners, indexerOwnerEntry{Token: k.token, Id: id, Owner: v})
+	}
+	bz, err := json.Marshal(indexerRecord{State: &state})
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	tmp := s.Path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(bz, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.Path); err != nil {
+		return err
+	}
+	s.records = 1
+	return nil
+}
@@ -0,0 +1,248 @@
+package ethcli
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func Test_TransferIndexer(t *testing.T) {
+	var (
+		erc20   = common.HexToAddress("0x2020202020202020202020202020202020202020")
+		erc721  = common.HexToAddress("0x7217217217217217217217217217217217217217")
+		erc1155 = common.HexToAddress("0x1155115511551155115511551155115511551155")
+		alice   = exampleAddress
+		bob     = common.HexToAddress("0x5555555555555555555555555555555555555555")
+		zero    = common.Address{}
+	)
+	ins, _ := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
+	batch, _ := ins.Events["TransferBatch"].Inputs.NonIndexed().Pack(
+		[]*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(5), big.NewInt(7)})
+	erc20Log := func(from, to common.Address, value int64) types.Log {
+		return types.Log{Address: erc20, Topics: []common.Hash{transferTopic, addressTopic(from), addressTopic(to)},
+			Data: common.LeftPadBytes(big.NewInt(value).Bytes(), 32)}
+	}
+	erc721Log := func(from, to common.Address, id int64) types.Log {
+		return types.Log{Address: erc721, Topics: []common.Hash{transferTopic, addressTopic(from), addressTopic(to), common.BigToHash(big.NewInt(id))}}
+	}
+
+	genesis := &types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1)}
+	block2 := &types.Header{Number: big.NewInt(2), Difficulty: big.NewInt(1), ParentHash: genesis.Hash()}
+
+	store, err := NewFileIndexerStore(filepath.Join(t.TempDir(), "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	indexer := NewTransferIndexer(store, []string{erc20.Hex(), erc721.Hex(), erc1155.Hex()})
+	err = indexer.ApplyBlock(genesis, []types.Log{
+		erc20Log(zero, alice, 100),
+		erc721Log(zero, alice, 7),
+		{Address: erc1155, Topics: []common.Hash{ins.Events["TransferBatch"].ID, addressTopic(alice), {}, addressTopic(alice)}, Data: batch},
+		// not an indexed contract
+		{Address: bob, Topics: []common.Hash{transferTopic, addressTopic(zero), addressTopic(bob)}, Data: common.LeftPadBytes(big.NewInt(1).Bytes(), 32)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = indexer.ApplyBlock(block2, []types.Log{
+		erc20Log(alice, bob, 30),
+		erc20Log(alice, bob, 20),
+		erc721Log(alice, bob, 7),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(store IndexerStore, token, holder common.Address, id *big.Int, expected int64) {
+		t.Helper()
+		v, _ := store.Balance(token, holder, id)
+		if v.Int64() != expected {
+			t.Fatalf("balance of %s in %s id %v: got %s want %d", holder.Hex(), token.Hex(), id, v, expected)
+		}
+	}
+	check(store, erc20, alice, nil, 50)
+	check(store, erc20, bob, nil, 50)
+	check(store, erc721, bob, nil, 1)
+	check(store, erc721, alice, nil, 0)
+	check(store, erc1155, alice, big.NewInt(2), 7)
+	check(store, bob, bob, nil, 0)
+	if owner, _ := store.Owner(erc721, big.NewInt(7)); owner != bob {
+		t.Fatalf("unexpected owner %s", owner.Hex())
+	}
+	if head, _ := store.Head(); len(head.Balances) != 4 {
+		t.Fatalf("expected one change per balance, got %+v", head.Balances)
+	}
+
+	// a block not building on the head is rejected
+	orphan := &types.Header{Number: big.NewInt(3), Difficulty: big.NewInt(1)}
+	if err := indexer.ApplyBlock(orphan, nil); err == nil {
+		t.Fatal("expected gap error")
+	}
+
+	// reopening the file store restores the state and the journal
+	reopened, err := NewFileIndexerStore(store.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(reopened, erc20, bob, nil, 50)
+	check(reopened, erc1155, alice, big.NewInt(1), 5)
+	indexer.Store = reopened
+	if err := indexer.Handle(ChainEvent{Type: ChainRevert, Header: block2}); err != nil {
+		t.Fatal(err)
+	}
+	check(reopened, erc20, alice, nil, 100)
+	check(reopened, erc20, bob, nil, 0)
+	check(reopened, erc721, alice, nil, 1)
+	if owner, _ := reopened.Owner(erc721, big.NewInt(7)); owner != alice {
+		t.Fatalf("owner not reverted: %s", owner.Hex())
+	}
+	if head, _ := reopened.Head(); head.Hash != genesis.Hash() {
+		t.Fatal("head not reverted")
+	}
+}
+
+func Test_TransferIndexerReorgTooDeep(t *testing.T) {
+	chain := newMockChain(3)
+	mock := chain.rpc(t)
+	cli := mock.client(t)
+	store := NewMemoryIndexerStore()
+	store.Depth = 1
+	indexer := NewTransferIndexer(store, []string{exampleToken.Hex()})
+
+	mint := types.Log{Address: exampleToken, Topics: []common.Hash{transferTopic, {}, addressTopic(exampleAddress)},
+		Data: common.LeftPadBytes(big.NewInt(10).Bytes(), 32)}
+	for n := 1; n < 3; n++ {
+		if err := indexer.ApplyBlock(chain.canonical[n], []types.Log{mint}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// both indexed blocks are replaced, but only the last one can be reverted
+	block1 := chain.canonical[1].Hash()
+	chain.reorg(1, 5, "b")
+	err := indexer.Run(context.Background(), cli, big.NewInt(0))
+	if !errors.Is(err, ErrReorgTooDeep) {
+		t.Fatalf("expected ErrReorgTooDeep, got %v", err)
+	}
+	if mock.count("eth_getLogs") != 0 {
+		t.Fatal("indexing restarted after a too deep reorg")
+	}
+	head, _ := store.Head()
+	if head == nil || head.Number != 1 || head.Hash != block1 {
+		t.Fatalf("unexpected head %+v", head)
+	}
+	if v, _ := store.Balance(exampleToken, exampleAddress, nil); v.Int64() != 10 {
+		t.Fatalf("balance %s, want 10", v)
+	}
+}
+
+func Test_FileIndexerStoreAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.jsonl")
+	store, err := NewFileIndexerStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.CompactAfter = 3
+	holder := exampleAddress
+	parent := common.Hash{}
+	for n := uint64(1); n <= 3; n++ {
+		block := &IndexedBlock{Number: n, Hash: common.BigToHash(new(big.Int).SetUint64(n)), ParentHash: parent,
+			Balances: []BalanceChange{{Token: exampleToken, Holder: holder, Old: new(big.Int).SetUint64(n - 1), New: new(big.Int).SetUint64(n)}}}
+		if err := store.Commit(block); err != nil {
+			t.Fatal(err)
+		}
+		parent = block.Hash
+	}
+	if _, err := store.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := func() int {
+		bz, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.Count(string(bz), "\n")
+	}
+	// three blocks were appended, the rollback compacted them into one snapshot
+	if n := lines(); n != 1 {
+		t.Fatalf("%d records after compaction", n)
+	}
+	block := &IndexedBlock{Number: 3, Hash: common.HexToHash("0x03b"), ParentHash: parent,
+		Balances: []BalanceChange{{Token: exampleToken, Holder: holder, Old: big.NewInt(2), New: big.NewInt(5)}}}
+	if err := store.Commit(block); err != nil {
+		t.Fatal(err)
+	}
+	if n := lines(); n != 2 {
+		t.Fatalf("%d records, want the snapshot and one block", n)
+	}
+
+	// a torn write at the end of the file is dropped on open
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	_, _ = f.WriteString(`{"commit":{"number":4`)
+	f.Close()
+	reopened, err := NewFileIndexerStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := reopened.Balance(exampleToken, holder, nil); v.Int64() != 5 {
+		t.Fatalf("balance %s, want 5", v)
+	}
+	if head, _ := reopened.Head(); head.Hash != block.Hash {
+		t.Fatalf("unexpected head %+v", head)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := reopened.Rollback(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v, _ := reopened.Balance(exampleToken, holder, nil); v.Sign() != 0 {
+		t.Fatalf("balance %s after rolling back every block", v)
+	}
+	if _, err := reopened.Rollback(); !errors.Is(err, ErrReorgTooDeep) {
+		t.Fatalf("expected ErrReorgTooDeep, got %v", err)
+	}
+}
+
+func Test_FileIndexerStoreWriteFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.jsonl")
+	store, err := NewFileIndexerStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := &IndexedBlock{Number: 1, Hash: common.HexToHash("0x01"),
+		Balances: []BalanceChange{{Token: exampleToken, Holder: exampleAddress, Old: big.NewInt(0), New: big.NewInt(1)}}}
+	if err := store.Commit(first); err != nil {
+		t.Fatal(err)
+	}
+
+	// a directory in place of the file makes every append fail
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir(path, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	second := &IndexedBlock{Number: 2, Hash: common.HexToHash("0x02"), ParentHash: first.Hash,
+		Balances: []BalanceChange{{Token: exampleToken, Holder: exampleAddress, Old: big.NewInt(1), New: big.NewInt(2)}}}
+	if err := store.Commit(second); err == nil {
+		t.Fatal("expected commit to fail")
+	}
+	if _, err := store.Rollback(); err == nil {
+		t.Fatal("expected rollback to fail")
+	}
+	if head, _ := store.Head(); head.Hash != first.Hash {
+		t.Fatalf("head moved to %+v after failed writes", head)
+	}
+	if v, _ := store.Balance(exampleToken, exampleAddress, nil); v.Int64() != 1 {
+		t.Fatalf("balance %s after failed writes, want 1", v)
+	}
+}
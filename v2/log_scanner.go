@@ -0,0 +1,252 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// logLimitErrors are fragments of the errors providers return when an eth_getLogs
+// range or result set is too large.
+var logLimitErrors = []string{
+	"query returned more than",
+	"block range is too wide",
+	"maximum block range",
+	"block range limit",
+	"block range exceeds",
+	"eth_getlogs is limited to",
+	"range is too large",
+	"range too large",
+	"limit exceeded",
+	"response size exceeded",
+	"response size should not",
+	"too many results",
+	"too many logs",
+	"logs matched by query exceeds",
+	"query timeout exceeded",
+}
+
+// IsLogLimitError reports whether err is a provider rejecting an eth_getLogs request as too large.
+func IsLogLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, v := range logLimitErrors {
+		if strings.Contains(msg, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Checkpointer persists the next block a LogScanner has to process.
+type Checkpointer interface {
+	Load() (next uint64, ok bool, err error)
+	Save(next uint64) error
+}
+
+// FileCheckpoint is a Checkpointer storing the next block as JSON in Path.
+type FileCheckpoint struct {
+	Path string
+}
+
+func (c *FileCheckpoint) Load() (uint64, bool, error) {
+	bz, err := os.ReadFile(c.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	var v struct {
+		Next uint64 `json:"next"`
+	}
+	if err := json.Unmarshal(bz, &v); err != nil {
+		return 0, false, err
+	}
+	return v.Next, true, nil
+}
+
+func (c *FileCheckpoint) Save(next uint64) error {
+	bz, err := json.Marshal(struct {
+		Next uint64 `json:"next"`
+	}{next})
+	if err != nil {
+		return err
+	}
+	tmp := c.Path + ".tmp"
+	if err := os.WriteFile(tmp, bz, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.Path)
+}
+
+// LogScanner walks a block range with eth_getLogs in adaptive chunks. A chunk rejected by
+// the provider as too large is halved and retried, every successful chunk grows the chunk
+// size again. Up to Workers chunks are fetched concurrently, but handlers always see the
+// chunks in block order.
+type LogScanner struct {
+	Client       *ethclient.Client
+	Query        ethereum.FilterQuery // Addresses and Topics; the block range is set by Scan
+	InitialChunk uint64
+	MinChunk     uint64
+	MaxChunk     uint64
+	Workers      int
+	Checkpoint   Checkpointer     // optional, resumes and records progress
+	IsLimitError func(error) bool // defaults to IsLogLimitError
+
+	mu    sync.Mutex
+	chunk uint64
+}
+
+// LogHandler receives the logs of [from, to] in block order.
+type LogHandler func(from, to uint64, logs []types.Log) error
+
+func NewLogScanner(cli *ethclient.Client, query ethereum.FilterQuery) *LogScanner {
+	return &LogScanner{
+		Client:       cli,
+		Query:        query,
+		InitialChunk: 2000,
+		MinChunk:     1,
+		MaxChunk:     100000,
+		Workers:      4,
+	}
+}
+
+// Scan processes [fromBlock, toBlock]; a nil toBlock means the head at the time of the call.
+// With a Checkpoint, a scan resumes after the last block handled by a previous run.
+func (s *LogScanner) Scan(ctx context.Context, fromBlock, toBlock *big.Int, handle LogHandler) error {
+	start := bigOrZero(fromBlock).Uint64()
+	var end uint64
+	if toBlock == nil {
+		head, err := s.Client.BlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+		end = head
+	} else {
+		end = toBlock.Uint64()
+	}
+	if s.Checkpoint != nil {
+		next, ok, err := s.Checkpoint.Load()
+		if err != nil {
+			return err
+		}
+		if ok && next > start {
+			start = next
+		}
+	}
+
+	s.mu.Lock()
+	if s.chunk == 0 {
+		s.chunk = max(s.InitialChunk, 1)
+	}
+	s.mu.Unlock()
+	workers := max(s.Workers, 1)
+
+	type result struct {
+		from, to uint64
+		logs     []types.Log
+		err      error
+	}
+	for cursor := start; cursor <= end; {
+		var ranges []result
+		for next := cursor; next <= end && len(ranges) < workers; {
+			last := min(next+s.chunkSize()-1, end)
+			ranges = append(ranges, result{from: next, to: last})
+			next = last + 1
+		}
+
+		var wg sync.WaitGroup
+		for i := range ranges {
+			wg.Add(1)
+			go func(r *result) {
+				defer wg.Done()
+				r.logs, r.err = s.fetch(ctx, r.from, r.to)
+			}(&ranges[i])
+		}
+		wg.Wait()
+
+		for _, r := range ranges {
+			if r.err != nil {
+				return r.err
+			}
+			if err := handle(r.from, r.to, r.logs); err != nil {
+				return err
+			}
+			if s.Checkpoint != nil {
+				if err := s.Checkpoint.Save(r.to + 1); err != nil {
+					return err
+				}
+			}
+			cursor = r.to + 1
+		}
+	}
+	return nil
+}
+
+// fetch returns the logs of [from, to], splitting the range in halves while the provider rejects it.
+// A range no larger than MinChunk is not split further; the provider's error is returned instead.
+func (s *LogScanner) fetch(ctx context.Context, from, to uint64) ([]types.Log, error) {
+	query := s.Query
+	query.BlockHash = nil
+	query.FromBlock = new(big.Int).SetUint64(from)
+	query.ToBlock = new(big.Int).SetUint64(to)
+	logs, err := s.Client.FilterLogs(ctx, query)
+	if err == nil {
+		s.grow()
+		return logs, nil
+	}
+
+	isLimit := s.IsLimitError
+	if isLimit == nil {
+		isLimit = IsLogLimitError
+	}
+	if !isLimit(err) || to-from+1 <= max(s.MinChunk, 1) {
+		return nil, err
+	}
+	mid := from + (to-from)/2
+	s.shrink(mid - from + 1)
+
+	left, err := s.fetch(ctx, from, mid)
+	if err != nil {
+		return nil, err
+	}
+	right, err := s.fetch(ctx, mid+1, to)
+	if err != nil {
+		return nil, err
+	}
+	return append(left, right...), nil
+}
+
+func (s *LogScanner) chunkSize() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.chunk
+}
+
+func (s *LogScanner) grow() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chunk += s.chunk/2 + 1
+	if s.MaxChunk > 0 && s.chunk > s.MaxChunk {
+		s.chunk = s.MaxChunk
+	}
+}
+
+func (s *LogScanner) shrink(size uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if size < s.chunk {
+		s.chunk = max(size, s.MinChunk, 1)
+	}
+}
@@ -0,0 +1,145 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// blockLogsRPC serves one log per block and rejects ranges wider than limit blocks.
+func blockLogsRPC(t *testing.T, limit uint64) *mockRPC {
+	return newMockRPC(t, map[string]mockHandler{
+		"eth_getLogs": func(params []json.RawMessage) (interface{}, *mockError) {
+			var q struct {
+				FromBlock hexutil.Uint64 `json:"fromBlock"`
+				ToBlock   hexutil.Uint64 `json:"toBlock"`
+			}
+			_ = json.Unmarshal(params[0], &q)
+			if uint64(q.ToBlock-q.FromBlock)+1 > limit {
+				return nil, &mockError{Code: -32005, Message: "query returned more than 10000 results"}
+			}
+			logs := []types.Log{}
+			for n := uint64(q.FromBlock); n <= uint64(q.ToBlock); n++ {
+				logs = append(logs, types.Log{Address: exampleToken, Topics: []common.Hash{transferTopic}, BlockNumber: n})
+			}
+			return logs, nil
+		},
+	})
+}
+
+func Test_LogScanner(t *testing.T) {
+	mock := blockLogsRPC(t, 50)
+	scanner := NewLogScanner(mock.client(t), ethereum.FilterQuery{Addresses: []common.Address{exampleToken}})
+	scanner.InitialChunk = 200
+
+	var blocks []uint64
+	next := uint64(10)
+	err := scanner.Scan(context.Background(), big.NewInt(10), big.NewInt(1000), func(from, to uint64, logs []types.Log) error {
+		if from != next {
+			t.Fatalf("expected chunk at %d, got %d", next, from)
+		}
+		next = to + 1
+		for _, l := range logs {
+			blocks = append(blocks, l.BlockNumber)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 991 {
+		t.Fatalf("expected 991 logs, got %d", len(blocks))
+	}
+	for i, n := range blocks {
+		if n != uint64(10+i) {
+			t.Fatalf("log %d out of order: block %d", i, n)
+		}
+	}
+	if scanner.chunkSize() >= 200 {
+		t.Fatalf("chunk size did not adapt: %d", scanner.chunkSize())
+	}
+}
+
+func Test_LogScannerMinChunk(t *testing.T) {
+	mock := blockLogsRPC(t, 5)
+	scanner := NewLogScanner(mock.client(t), ethereum.FilterQuery{})
+	scanner.InitialChunk, scanner.MinChunk, scanner.Workers = 100, 20, 1
+
+	err := scanner.Scan(context.Background(), big.NewInt(0), big.NewInt(99), func(from, to uint64, logs []types.Log) error {
+		t.Fatalf("unexpected chunk [%d, %d]", from, to)
+		return nil
+	})
+	if !IsLogLimitError(err) {
+		t.Fatalf("expected the provider's limit error, got %v", err)
+	}
+	if calls := mock.count("eth_getLogs"); calls != 4 {
+		t.Fatalf("split below MinChunk: %d requests", calls)
+	}
+}
+
+func Test_LogScannerCheckpoint(t *testing.T) {
+	mock := blockLogsRPC(t, 1000)
+	checkpoint := &FileCheckpoint{Path: filepath.Join(t.TempDir(), "scan.json")}
+	scanner := NewLogScanner(mock.client(t), ethereum.FilterQuery{})
+	scanner.InitialChunk, scanner.MaxChunk, scanner.Workers = 10, 10, 1
+	scanner.Checkpoint = checkpoint
+
+	stop := errors.New("stop")
+	err := scanner.Scan(context.Background(), big.NewInt(0), big.NewInt(99), func(from, to uint64, logs []types.Log) error {
+		if from == 50 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected stop, got %v", err)
+	}
+	if next, ok, _ := checkpoint.Load(); !ok || next != 50 {
+		t.Fatalf("unexpected checkpoint %d %v", next, ok)
+	}
+
+	var first uint64 = 1 << 63
+	err = scanner.Scan(context.Background(), big.NewInt(0), big.NewInt(99), func(from, to uint64, logs []types.Log) error {
+		first = min(first, from)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != 50 {
+		t.Fatalf("scan did not resume from checkpoint: %d", first)
+	}
+	if next, _, _ := checkpoint.Load(); next != 100 {
+		t.Fatalf("unexpected final checkpoint %d", next)
+	}
+}
+
+func Test_IsLogLimitError(t *testing.T) {
+	for _, msg := range []string{
+		"query returned more than 10000 results",
+		"eth_getLogs is limited to a 10,000 block range",
+		"Log response size exceeded. You can make eth_getLogs requests with up to a 2K block range",
+		"block range is too wide",
+		"exceed maximum block range: 50000",
+	} {
+		if !IsLogLimitError(errors.New(msg)) {
+			t.Fatalf("%q not detected", msg)
+		}
+	}
+	for _, msg := range []string{
+		"execution reverted",
+		"invalid block range params",
+	} {
+		if IsLogLimitError(errors.New(msg)) {
+			t.Fatalf("%q detected as a limit error", msg)
+		}
+	}
+}
@@ -0,0 +1,279 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const (
+	DefaultIPFSGateway    = "https://ipfs.io/ipfs/"
+	DefaultArweaveGateway = "https://arweave.net/"
+)
+
+// ErrUnsupportedURI is returned for token URIs whose scheme the resolver cannot fetch.
+var ErrUnsupportedURI = errors.New("unsupported uri")
+
+// MetadataAttribute is one entry of the attributes list of ERC721/ERC1155 metadata.
+// Value keeps its JSON type: string, float64 or bool.
+type MetadataAttribute struct {
+	TraitType   string      `json:"trait_type"`
+	Value       interface{} `json:"value"`
+	DisplayType string      `json:"display_type,omitempty"`
+	MaxValue    interface{} `json:"max_value,omitempty"`
+}
+
+// Metadata is the JSON document a token URI points to. Raw holds the document as fetched.
+type Metadata struct {
+	Name            string                 `json:"name"`
+	Description     string                 `json:"description"`
+	Image           string                 `json:"image"`
+	ImageData       string                 `json:"image_data,omitempty"`
+	ExternalURL     string                 `json:"external_url,omitempty"`
+	AnimationURL    string                 `json:"animation_url,omitempty"`
+	BackgroundColor string                 `json:"background_color,omitempty"`
+	Decimals        *int                   `json:"decimals,omitempty"`
+	Attributes      []MetadataAttribute    `json:"attributes,omitempty"`
+	Properties      map[string]interface{} `json:"properties,omitempty"`
+	Raw             json.RawMessage        `json:"-"`
+}
+
+// UnmarshalJSON accepts attributes both as the usual list and as an object mapping trait types to values.
+func (m *Metadata) UnmarshalJSON(data []byte) error {
+	type plain Metadata
+	var doc struct {
+		plain
+		Attributes json.RawMessage `json:"attributes"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	*m = Metadata(doc.plain)
+	m.Raw = append(json.RawMessage(nil), data...)
+
+	attrs := strings.TrimSpace(string(doc.Attributes))
+	switch {
+	case attrs == "" || attrs == "null":
+	case attrs[0] == '[':
+		if err := json.Unmarshal(doc.Attributes, &m.Attributes); err != nil {
+			return fmt.Errorf("attributes: %w", err)
+		}
+	case attrs[0] == '{':
+		var values map[string]interface{}
+		if err := json.Unmarshal(doc.Attributes, &values); err != nil {
+			return fmt.Errorf("attributes: %w", err)
+		}
+		for k, v := range values {
+			m.Attributes = append(m.Attributes, MetadataAttribute{TraitType: k, Value: v})
+		}
+		sort.Slice(m.Attributes, func(i, j int) bool { return m.Attributes[i].TraitType < m.Attributes[j].TraitType })
+	default:
+		return fmt.Errorf("attributes: unexpected %s", attrs)
+	}
+	return nil
+}
+
+// MetadataResolver reads token URIs and fetches the metadata they point to, caching documents by URI.
+// ipfs:// and ar:// URIs are fetched through the configured gateways.
+type MetadataResolver struct {
+	Client         *ethclient.Client
+	HTTPClient     *http.Client
+	IPFSGateway    string
+	ArweaveGateway string
+	// MaxSize limits the size of a fetched document in bytes.
+	MaxSize int64
+	// CacheSize is the number of documents kept; 0 disables caching.
+	CacheSize int
+
+	mu    sync.Mutex
+	cache map[string]*Metadata
+}
+
+func NewMetadataResolver(cli *ethclient.Client) *MetadataResolver {
+	return &MetadataResolver{
+		Client:         cli,
+		HTTPClient:     http.DefaultClient,
+		IPFSGateway:    DefaultIPFSGateway,
+		ArweaveGateway: DefaultArweaveGateway,
+		MaxSize:        1 << 20,
+		CacheSize:      1024,
+	}
+}
+
+// ERC1155TokenURI substitutes the {id} template of an ERC1155 uri with the lowercase, 64 character hex id.
+func ERC1155TokenURI(uri string, id *big.Int) string {
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", id))
+}
+
+// TokenURI returns the metadata URI of token id, reading tokenURI for ERC721 or uri for ERC1155.
+func (r *MetadataResolver) TokenURI(ctx context.Context, token string, standard TokenStandard, id *big.Int, blockNumber *big.Int) (string, error) {
+	switch standard {
+	case StandardERC721:
+		return ERC721TokenURI(ctx, r.Client, token, id, blockNumber)
+	case StandardERC1155:
+		uri, err := ERC1155Uri(ctx, r.Client, token, id, blockNumber)
+		if err != nil {
+			return "", err
+		}
+		return ERC1155TokenURI(uri, id), nil
+	}
+	return "", fmt.Errorf("token uri of %s not supported", standard)
+}
+
+// TokenMetadata reads the URI of token id and resolves it.
+func (r *MetadataResolver) TokenMetadata(ctx context.Context, token string, standard TokenStandard, id *big.Int, blockNumber *big.Int) (*Metadata, error) {
+	uri, err := r.TokenURI(ctx, token, standard, id, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	return r.Resolve(ctx, uri)
+}
+
+// GatewayURL maps uri to the http(s) URL it is fetched from. http(s) URLs are returned unchanged.
+// It can also be used for the Image and AnimationURL of a document.
+func (r *MetadataResolver) GatewayURL(uri string) (string, error) {
+	uri = strings.TrimSpace(uri)
+	scheme, rest, _ := strings.Cut(uri, "://")
+	switch strings.ToLower(scheme) {
+	case "http", "https":
+		return uri, nil
+	case "ipfs":
+		rest = strings.TrimPrefix(rest, "ipfs/")
+		return joinGateway(r.IPFSGateway, DefaultIPFSGateway, rest), nil
+	case "ar":
+		return joinGateway(r.ArweaveGateway, DefaultArweaveGateway, rest), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedURI, uri)
+}
+
+// Resolve fetches and parses the metadata document at uri. Documents in data: URIs are decoded in place
+// and not cached. The returned Metadata may be shared with other callers and must not be modified.
+func (r *MetadataResolver) Resolve(ctx context.Context, uri string) (*Metadata, error) {
+	uri = strings.TrimSpace(uri)
+	if strings.HasPrefix(uri, "data:") {
+		data, err := decodeDataURI(uri)
+		if err != nil {
+			return nil, err
+		}
+		return parseMetadata(data)
+	}
+
+	if m, ok := r.cached(uri); ok {
+		return m, nil
+	}
+	target, err := r.GatewayURL(uri)
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.fetch(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	m, err := parseMetadata(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", uri, err)
+	}
+	r.store(uri, m)
+	return m, nil
+}
+
+func (r *MetadataResolver) fetch(ctx context.Context, target string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	client := r.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: %s", target, resp.Status)
+	}
+
+	limit := r.MaxSize
+	if limit <= 0 {
+		limit = 1 << 20
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("fetch %s: document larger than %d bytes", target, limit)
+	}
+	return data, nil
+}
+
+func (r *MetadataResolver) cached(uri string) (*Metadata, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.cache[uri]
+	return m, ok
+}
+
+func (r *MetadataResolver) store(uri string, m *Metadata) {
+	if r.CacheSize <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cache == nil {
+		r.cache = make(map[string]*Metadata)
+	}
+	if len(r.cache) >= r.CacheSize {
+		// evict an arbitrary entry
+		for k := range r.cache {
+			delete(r.cache, k)
+			break
+		}
+	}
+	r.cache[uri] = m
+}
+
+func parseMetadata(data []byte) (*Metadata, error) {
+	m := new(Metadata)
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// decodeDataURI returns the payload of an RFC 2397 data: URI, base64 or percent-encoded.
+func decodeDataURI(uri string) ([]byte, error) {
+	header, payload, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed data uri", ErrUnsupportedURI)
+	}
+	if strings.HasSuffix(strings.ToLower(header), ";base64") {
+		return base64.StdEncoding.DecodeString(payload)
+	}
+	decoded, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(decoded), nil
+}
+
+func joinGateway(gateway, fallback, path string) string {
+	if gateway == "" {
+		gateway = fallback
+	}
+	return strings.TrimSuffix(gateway, "/") + "/" + path
+}
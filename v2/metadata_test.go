@@ -0,0 +1,116 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_ERC1155TokenURI(t *testing.T) {
+	got := ERC1155TokenURI("https://token-cdn-domain/{id}.json", big.NewInt(314592))
+	want := "https://token-cdn-domain/000000000000000000000000000000000000000000000000000000000004cce0.json"
+	if got != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func Test_MetadataResolver(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/ipfs/QmToken/1":
+			_, _ = w.Write([]byte(`{"name":"One","image":"ipfs://QmImage","attributes":[{"trait_type":"Level","value":5,"display_type":"number"},{"trait_type":"Eyes","value":"blue"}]}`))
+		case "/arweave/tx1":
+			_, _ = w.Write([]byte(`{"name":"Two","attributes":{"b":"x","a":true}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	r := NewMetadataResolver(nil)
+	r.HTTPClient = server.Client()
+	r.IPFSGateway = server.URL + "/ipfs/"
+	r.ArweaveGateway = server.URL + "/arweave"
+	ctx := context.Background()
+
+	m, err := r.Resolve(ctx, "ipfs://ipfs/QmToken/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "One" || len(m.Attributes) != 2 || m.Attributes[0].Value != float64(5) || m.Attributes[1].Value != "blue" {
+		t.Fatalf("unexpected metadata %+v", m)
+	}
+	if image, _ := r.GatewayURL(m.Image); image != server.URL+"/ipfs/QmImage" {
+		t.Fatalf("image url %s", image)
+	}
+	if _, err := r.Resolve(ctx, "ipfs://QmToken/1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Resolve(ctx, "ipfs://ipfs/QmToken/1"); err != nil || hits.Load() != 2 {
+		t.Fatalf("cached resolve: %v, %d requests", err, hits.Load())
+	}
+
+	m, err = r.Resolve(ctx, "ar://tx1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Attributes) != 2 || m.Attributes[0].TraitType != "a" || m.Attributes[0].Value != true {
+		t.Fatalf("unexpected attributes %+v", m.Attributes)
+	}
+
+	doc := base64.StdEncoding.EncodeToString([]byte(`{"name":"Inline","description":"on chain"}`))
+	m, err = r.Resolve(ctx, "data:application/json;base64,"+doc)
+	if err != nil || m.Name != "Inline" || m.Description != "on chain" {
+		t.Fatalf("data uri: %+v %v", m, err)
+	}
+	m, err = r.Resolve(ctx, `data:application/json,{"name":"Plain%20text"}`)
+	if err != nil || m.Name != "Plain text" {
+		t.Fatalf("data uri: %+v %v", m, err)
+	}
+
+	if _, err := r.Resolve(ctx, server.URL+"/missing"); err == nil {
+		t.Fatal("expected error for missing document")
+	}
+	if _, err := r.Resolve(ctx, "ftp://example.com/1.json"); !errors.Is(err, ErrUnsupportedURI) {
+		t.Fatalf("got %v, want ErrUnsupportedURI", err)
+	}
+}
+
+func Test_MetadataResolverTokenMetadata(t *testing.T) {
+	id := big.NewInt(10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+ERC1155TokenURI("{id}", id)+".json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"name":"Sword","decimals":0,"properties":{"rarity":"epic"}}`))
+	}))
+	defer server.Close()
+
+	rpc := newMockRPC(t, map[string]mockHandler{
+		"eth_call": mockContracts([]string{openzeppelinIERC1155Abi}, map[string]mockMethod{
+			"uri": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{server.URL + "/{id}.json"}, nil
+			},
+		}),
+	})
+
+	r := NewMetadataResolver(rpc.client(t))
+	r.HTTPClient = server.Client()
+	m, err := r.TokenMetadata(context.Background(), exampleToken.Hex(), StandardERC1155, id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Name != "Sword" || m.Decimals == nil || *m.Decimals != 0 || m.Properties["rarity"] != "epic" {
+		t.Fatalf("unexpected metadata %+v", m)
+	}
+}
@@ -0,0 +1,237 @@
+package ethcli
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// mockHandler answers one JSON-RPC method. A non-nil *mockError is returned to the client as a JSON-RPC error.
+type mockHandler func(params []json.RawMessage) (interface{}, *mockError)
+
+type mockError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type mockRequest struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+type mockResponse struct {
+	Version string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *mockError      `json:"error,omitempty"`
+}
+
+// mockRPC is a minimal JSON-RPC server standing in for a node or bundler.
+type mockRPC struct {
+	*httptest.Server
+	mu       sync.Mutex
+	handlers map[string]mockHandler
+	calls    map[string]int
+}
+
+func newMockRPC(t *testing.T, handlers map[string]mockHandler) *mockRPC {
+	m := &mockRPC{handlers: handlers, calls: map[string]int{}}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.serve))
+	t.Cleanup(m.Close)
+	return m
+}
+
+func (m *mockRPC) client(t *testing.T) *ethclient.Client {
+	cli, err := ethclient.Dial(m.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cli.Close)
+	return cli
+}
+
+func (m *mockRPC) count(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls[method]
+}
+
+func (m *mockRPC) serve(w http.ResponseWriter, r *http.Request) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if len(raw) > 0 && raw[0] == '[' {
+		var reqs []mockRequest
+		_ = json.Unmarshal(raw, &reqs)
+		resps := make([]mockResponse, 0, len(reqs))
+		for _, req := range reqs {
+			resps = append(resps, m.handle(req))
+		}
+		_ = json.NewEncoder(w).Encode(resps)
+		return
+	}
+	var req mockRequest
+	_ = json.Unmarshal(raw, &req)
+	_ = json.NewEncoder(w).Encode(m.handle(req))
+}
+
+func (m *mockRPC) handle(req mockRequest) mockResponse {
+	m.mu.Lock()
+	m.calls[req.Method]++
+	handler, ok := m.handlers[req.Method]
+	m.mu.Unlock()
+
+	resp := mockResponse{Version: "2.0", ID: req.ID}
+	if !ok {
+		resp.Error = &mockError{Code: -32601, Message: "method not found: " + req.Method}
+		return resp
+	}
+	result, rpcErr := handler(req.Params)
+	if rpcErr != nil {
+		resp.Error = rpcErr
+		return resp
+	}
+	if result == nil {
+		result = json.RawMessage("null")
+	}
+	resp.Result = result
+	return resp
+}
+
+// mockMethod answers one contract method with its output values.
+type mockMethod func(to common.Address, args []interface{}) ([]interface{}, *mockError)
+
+var mockRevert = &mockError{Code: 3, Message: "execution reverted"}
+
+// mockContracts serves eth_call by decoding the calldata with the given ABIs and dispatching on the method name.
+// Unknown selectors revert.
+func mockContracts(abis []string, methods map[string]mockMethod) mockHandler {
+	parsed := make([]abi.ABI, 0, len(abis))
+	for _, v := range abis {
+		ins, err := abi.JSON(strings.NewReader(v))
+		if err != nil {
+			panic(err)
+		}
+		parsed = append(parsed, ins)
+	}
+	return func(params []json.RawMessage) (interface{}, *mockError) {
+		var call struct {
+			To    common.Address `json:"to"`
+			Data  hexutil.Bytes  `json:"data"`
+			Input hexutil.Bytes  `json:"input"`
+		}
+		_ = json.Unmarshal(params[0], &call)
+		data := call.Data
+		if len(data) == 0 {
+			data = call.Input
+		}
+		if len(data) < 4 {
+			return nil, mockRevert
+		}
+		for _, ins := range parsed {
+			method, err := ins.MethodById(data[:4])
+			if err != nil {
+				continue
+			}
+			handler, ok := methods[method.Name]
+			if !ok {
+				continue
+			}
+			args, err := method.Inputs.Unpack(data[4:])
+			if err != nil {
+				return nil, &mockError{Code: -32602, Message: err.Error()}
+			}
+			outputs, rpcErr := handler(call.To, args)
+			if rpcErr != nil {
+				return nil, rpcErr
+			}
+			bz, err := method.Outputs.Pack(outputs...)
+			if err != nil {
+				return nil, &mockError{Code: -32603, Message: err.Error()}
+			}
+			return hexutil.Bytes(bz), nil
+		}
+		return nil, mockRevert
+	}
+}
+
+// mockTxPool accepts raw transactions and mines each one immediately, building its receipt with mine.
+type mockTxPool struct {
+	mu       sync.Mutex
+	sent     []*types.Transaction
+	receipts map[common.Hash]*types.Receipt
+	mine     func(tx *types.Transaction) *types.Receipt
+}
+
+func newMockTxPool(mine func(tx *types.Transaction) *types.Receipt) *mockTxPool {
+	return &mockTxPool{receipts: map[common.Hash]*types.Receipt{}, mine: mine}
+}
+
+// handlers returns the methods SendLegacyTx and receipt polling need, for chain id 1.
+func (p *mockTxPool) handlers() map[string]mockHandler {
+	return map[string]mockHandler{
+		"eth_chainId": func([]json.RawMessage) (interface{}, *mockError) {
+			return "0x1", nil
+		},
+		"eth_getTransactionCount": func([]json.RawMessage) (interface{}, *mockError) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			return hexutil.Uint64(len(p.sent)), nil
+		},
+		"eth_gasPrice": func([]json.RawMessage) (interface{}, *mockError) {
+			return "0x3b9aca00", nil
+		},
+		"eth_estimateGas": func([]json.RawMessage) (interface{}, *mockError) {
+			return "0x30d40", nil
+		},
+		"eth_sendRawTransaction": func(params []json.RawMessage) (interface{}, *mockError) {
+			var raw hexutil.Bytes
+			_ = json.Unmarshal(params[0], &raw)
+			tx := new(types.Transaction)
+			if err := tx.UnmarshalBinary(raw); err != nil {
+				return nil, &mockError{Code: -32602, Message: err.Error()}
+			}
+			receipt := &types.Receipt{Status: types.ReceiptStatusSuccessful, Logs: []*types.Log{}}
+			if p.mine != nil {
+				receipt = p.mine(tx)
+			}
+			receipt.TxHash = tx.Hash()
+			receipt.BlockNumber = big.NewInt(1)
+			p.mu.Lock()
+			p.sent = append(p.sent, tx)
+			p.receipts[tx.Hash()] = receipt
+			p.mu.Unlock()
+			return tx.Hash(), nil
+		},
+		"eth_getTransactionReceipt": func(params []json.RawMessage) (interface{}, *mockError) {
+			var hash common.Hash
+			_ = json.Unmarshal(params[0], &hash)
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if r, ok := p.receipts[hash]; ok {
+				return r, nil
+			}
+			return nil, nil
+		},
+	}
+}
+
+func (p *mockTxPool) transactions() []*types.Transaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*types.Transaction(nil), p.sent...)
+}
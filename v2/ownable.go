@@ -0,0 +1,57 @@
+package ethcli
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+var (
+	openzeppelinOwnable2StepAbi = `[{"inputs":[],"name":"owner","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"pendingOwner","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"newOwner","type":"address"}],"name":"transferOwnership","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[],"name":"renounceOwnership","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[],"name":"acceptOwnership","outputs":[],"stateMutability":"nonpayable","type":"function"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"previousOwner","type":"address"},{"indexed":true,"internalType":"address","name":"newOwner","type":"address"}],"name":"OwnershipTransferred","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"previousOwner","type":"address"},{"indexed":true,"internalType":"address","name":"newOwner","type":"address"}],"name":"OwnershipTransferStarted","type":"event"}]`
+)
+
+func OwnableOwner(ctx context.Context, cli *ethclient.Client, contract string, blockNumber *big.Int) (string, error) {
+	owner, err := callResult[common.Address](ctx, cli, ownableABI, contract, "owner", blockNumber)
+	if err != nil {
+		return "", err
+	}
+	return owner.Hex(), nil
+}
+
+// OwnablePendingOwner returns the owner nominated by an Ownable2Step transferOwnership who has not accepted yet,
+// or the zero address.
+func OwnablePendingOwner(ctx context.Context, cli *ethclient.Client, contract string, blockNumber *big.Int) (string, error) {
+	owner, err := callResult[common.Address](ctx, cli, ownableABI, contract, "pendingOwner", blockNumber)
+	if err != nil {
+		return "", err
+	}
+	return owner.Hex(), nil
+}
+
+// OwnableTransferOwnership transfers ownership to newOwner. With Ownable2Step it only nominates newOwner,
+// who must call OwnableAcceptOwnership.
+func OwnableTransferOwnership(ctx context.Context, cli *ethclient.Client, key string, contract string, newOwner string) (string, error) {
+	return sendContract(ctx, cli, key, ownableABI, contract, "transferOwnership", common.HexToAddress(newOwner))
+}
+
+func OwnableRenounceOwnership(ctx context.Context, cli *ethclient.Client, key string, contract string) (string, error) {
+	return sendContract(ctx, cli, key, ownableABI, contract, "renounceOwnership")
+}
+
+func OwnableAcceptOwnership(ctx context.Context, cli *ethclient.Client, key string, contract string) (string, error) {
+	return sendContract(ctx, cli, key, ownableABI, contract, "acceptOwnership")
+}
+
+func OwnableTransferOwnershipData(newOwner string) ([]byte, error) {
+	return ownableABI.Pack("transferOwnership", common.HexToAddress(newOwner))
+}
+
+func OwnableRenounceOwnershipData() ([]byte, error) {
+	return ownableABI.Pack("renounceOwnership")
+}
+
+func OwnableAcceptOwnershipData() ([]byte, error) {
+	return ownableABI.Pack("acceptOwnership")
+}
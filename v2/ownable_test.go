@@ -0,0 +1,41 @@
+package ethcli
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func Test_Ownable(t *testing.T) {
+	pending := common.HexToAddress("0x9000000000000000000000000000000000000004")
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_call": mockContracts([]string{openzeppelinOwnable2StepAbi}, map[string]mockMethod{
+			"owner": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{exampleAddress}, nil
+			},
+			"pendingOwner": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{pending}, nil
+			},
+		}),
+	})
+	cli := mock.client(t)
+
+	owner, err := OwnableOwner(context.Background(), cli, exampleToken.Hex(), nil)
+	if err != nil || owner != exampleAddress.Hex() {
+		t.Fatalf("owner %s %v", owner, err)
+	}
+	next, err := OwnablePendingOwner(context.Background(), cli, exampleToken.Hex(), nil)
+	if err != nil || next != pending.Hex() {
+		t.Fatalf("pendingOwner %s %v", next, err)
+	}
+
+	data, err := OwnableTransferOwnershipData(pending.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, err := ownableABI.Methods["transferOwnership"].Inputs.Unpack(data[4:])
+	if err != nil || args[0].(common.Address) != pending {
+		t.Fatalf("transferOwnership args %v %v", args, err)
+	}
+}
@@ -0,0 +1,395 @@
+package ethcli
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// Permit2Address is the canonical Uniswap Permit2 deployment, identical on every chain.
+const Permit2Address = "0x000000000022D473030F116dDEE9F6B43aC78BA3"
+
+var (
+	uniswapPermit2Abi      = `[{"inputs":[],"name":"DOMAIN_SEPARATOR","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"user","type":"address"},{"internalType":"address","name":"token","type":"address"},{"internalType":"address","name":"spender","type":"address"}],"name":"allowance","outputs":[{"internalType":"uint160","name":"amount","type":"uint160"},{"internalType":"uint48","name":"expiration","type":"uint48"},{"internalType":"uint48","name":"nonce","type":"uint48"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"token","type":"address"},{"internalType":"address","name":"spender","type":"address"},{"internalType":"uint160","name":"amount","type":"uint160"},{"internalType":"uint48","name":"expiration","type":"uint48"}],"name":"approve","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"token","type":"address"},{"internalType":"address","name":"spender","type":"address"},{"internalType":"uint48","name":"newNonce","type":"uint48"}],"name":"invalidateNonces","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"uint256","name":"wordPos","type":"uint256"},{"internalType":"uint256","name":"mask","type":"uint256"}],"name":"invalidateUnorderedNonces","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"","type":"address"},{"internalType":"uint256","name":"","type":"uint256"}],"name":"nonceBitmap","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"owner","type":"address"},{"components":[{"components":[{"internalType":"address","name":"token","type":"address"},{"internalType":"uint160","name":"amount","type":"uint160"},{"internalType":"uint48","name":"expiration","type":"uint48"},{"internalType":"uint48","name":"nonce","type":"uint48"}],"internalType":"struct IAllowanceTransfer.PermitDetails","name":"details","type":"tuple"},{"internalType":"address","name":"spender","type":"address"},{"internalType":"uint256","name":"sigDeadline","type":"uint256"}],"internalType":"struct IAllowanceTransfer.PermitSingle","name":"permitSingle","type":"tuple"},{"internalType":"bytes","name":"signature","type":"bytes"}],"name":"permit","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"components":[{"components":[{"internalType":"address","name":"token","type":"address"},{"internalType":"uint256","name":"amount","type":"uint256"}],"internalType":"struct ISignatureTransfer.TokenPermissions","name":"permitted","type":"tuple"},{"internalType":"uint256","name":"nonce","type":"uint256"},{"internalType":"uint256","name":"deadline","type":"uint256"}],"internalType":"struct ISignatureTransfer.PermitTransferFrom","name":"permit","type":"tuple"},{"components":[{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"requestedAmount","type":"uint256"}],"internalType":"struct ISignatureTransfer.SignatureTransferDetails","name":"transferDetails","type":"tuple"},{"internalType":"address","name":"owner","type":"address"},{"internalType":"bytes","name":"signature","type":"bytes"}],"name":"permitTransferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"components":[{"components":[{"internalType":"address","name":"token","type":"address"},{"internalType":"uint256","name":"amount","type":"uint256"}],"internalType":"struct ISignatureTransfer.TokenPermissions","name":"permitted","type":"tuple"},{"internalType":"uint256","name":"nonce","type":"uint256"},{"internalType":"uint256","name":"deadline","type":"uint256"}],"internalType":"struct ISignatureTransfer.PermitTransferFrom","name":"permit","type":"tuple"},{"components":[{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"requestedAmount","type":"uint256"}],"internalType":"struct ISignatureTransfer.SignatureTransferDetails","name":"transferDetails","type":"tuple"},{"internalType":"address","name":"owner","type":"address"},{"internalType":"bytes32","name":"witness","type":"bytes32"},{"internalType":"string","name":"witnessTypeString","type":"string"},{"internalType":"bytes","name":"signature","type":"bytes"}],"name":"permitWitnessTransferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"from","type":"address"},{"internalType":"address","name":"to","type":"address"},{"internalType":"uint160","name":"amount","type":"uint160"},{"internalType":"address","name":"token","type":"address"}],"name":"transferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+	uniswapPermit2BatchAbi = `[{"inputs":[{"internalType":"address","name":"owner","type":"address"},{"components":[{"components":[{"internalType":"address","name":"token","type":"address"},{"internalType":"uint160","name":"amount","type":"uint160"},{"internalType":"uint48","name":"expiration","type":"uint48"},{"internalType":"uint48","name":"nonce","type":"uint48"}],"internalType":"struct IAllowanceTransfer.PermitDetails[]","name":"details","type":"tuple[]"},{"internalType":"address","name":"spender","type":"address"},{"internalType":"uint256","name":"sigDeadline","type":"uint256"}],"internalType":"struct IAllowanceTransfer.PermitBatch","name":"permitBatch","type":"tuple"},{"internalType":"bytes","name":"signature","type":"bytes"}],"name":"permit","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"components":[{"components":[{"internalType":"address","name":"token","type":"address"},{"internalType":"uint256","name":"amount","type":"uint256"}],"internalType":"struct ISignatureTransfer.TokenPermissions[]","name":"permitted","type":"tuple[]"},{"internalType":"uint256","name":"nonce","type":"uint256"},{"internalType":"uint256","name":"deadline","type":"uint256"}],"internalType":"struct ISignatureTransfer.PermitBatchTransferFrom","name":"permit","type":"tuple"},{"components":[{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"requestedAmount","type":"uint256"}],"internalType":"struct ISignatureTransfer.SignatureTransferDetails[]","name":"transferDetails","type":"tuple[]"},{"internalType":"address","name":"owner","type":"address"},{"internalType":"bytes","name":"signature","type":"bytes"}],"name":"permitTransferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"components":[{"components":[{"internalType":"address","name":"token","type":"address"},{"internalType":"uint256","name":"amount","type":"uint256"}],"internalType":"struct ISignatureTransfer.TokenPermissions[]","name":"permitted","type":"tuple[]"},{"internalType":"uint256","name":"nonce","type":"uint256"},{"internalType":"uint256","name":"deadline","type":"uint256"}],"internalType":"struct ISignatureTransfer.PermitBatchTransferFrom","name":"permit","type":"tuple"},{"components":[{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"requestedAmount","type":"uint256"}],"internalType":"struct ISignatureTransfer.SignatureTransferDetails[]","name":"transferDetails","type":"tuple[]"},{"internalType":"address","name":"owner","type":"address"},{"internalType":"bytes32","name":"witness","type":"bytes32"},{"internalType":"string","name":"witnessTypeString","type":"string"},{"internalType":"bytes","name":"signature","type":"bytes"}],"name":"permitWitnessTransferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"components":[{"internalType":"address","name":"from","type":"address"},{"internalType":"address","name":"to","type":"address"},{"internalType":"uint160","name":"amount","type":"uint160"},{"internalType":"address","name":"token","type":"address"}],"internalType":"struct IAllowanceTransfer.AllowanceTransferDetails[]","name":"transferDetails","type":"tuple[]"}],"name":"transferFrom","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+)
+
+// PermitDetails mirrors IAllowanceTransfer.PermitDetails.
+type PermitDetails struct {
+	Token      common.Address
+	Amount     *big.Int // uint160
+	Expiration *big.Int // uint48
+	Nonce      *big.Int // uint48
+}
+
+// PermitSingle mirrors IAllowanceTransfer.PermitSingle.
+type PermitSingle struct {
+	Details     PermitDetails
+	Spender     common.Address
+	SigDeadline *big.Int
+}
+
+// PermitBatch mirrors IAllowanceTransfer.PermitBatch.
+type PermitBatch struct {
+	Details     []PermitDetails
+	Spender     common.Address
+	SigDeadline *big.Int
+}
+
+// AllowanceTransferDetails mirrors IAllowanceTransfer.AllowanceTransferDetails.
+type AllowanceTransferDetails struct {
+	From   common.Address
+	To     common.Address
+	Amount *big.Int // uint160
+	Token  common.Address
+}
+
+// TokenPermissions mirrors ISignatureTransfer.TokenPermissions.
+type TokenPermissions struct {
+	Token  common.Address
+	Amount *big.Int
+}
+
+// PermitTransferFrom mirrors ISignatureTransfer.PermitTransferFrom.
+// The spender is not part of the struct, it is bound to msg.sender on chain
+// and therefore passed separately when signing.
+type PermitTransferFrom struct {
+	Permitted TokenPermissions
+	Nonce     *big.Int
+	Deadline  *big.Int
+}
+
+// PermitBatchTransferFrom mirrors ISignatureTransfer.PermitBatchTransferFrom.
+type PermitBatchTransferFrom struct {
+	Permitted []TokenPermissions
+	Nonce     *big.Int
+	Deadline  *big.Int
+}
+
+// SignatureTransferDetails mirrors ISignatureTransfer.SignatureTransferDetails.
+type SignatureTransferDetails struct {
+	To              common.Address
+	RequestedAmount *big.Int
+}
+
+// PackedAllowance is the (amount, expiration, nonce) tuple returned by Permit2.allowance.
+type PackedAllowance struct {
+	Amount     *big.Int
+	Expiration *big.Int
+	Nonce      *big.Int
+}
+
+// Permit2Witness describes the extra struct bound into a permitWitnessTransferFrom signature.
+// Types must contain TypeName and every struct type it references.
+type Permit2Witness struct {
+	TypeName string
+	Types    apitypes.Types
+	Value    map[string]interface{}
+}
+
+var (
+	permit2DetailsTypes = []apitypes.Type{
+		{Name: "token", Type: "address"},
+		{Name: "amount", Type: "uint160"},
+		{Name: "expiration", Type: "uint48"},
+		{Name: "nonce", Type: "uint48"},
+	}
+	permit2TokenPermissionsTypes = []apitypes.Type{
+		{Name: "token", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+	}
+	permit2DomainTypes = []apitypes.Type{
+		{Name: "name", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	}
+)
+
+func Permit2Allowance(ctx context.Context, cli *ethclient.Client, owner, token, spender string, blockNumber *big.Int) (*PackedAllowance, error) {
+	results, err := callContract(ctx, cli, permit2ABI, Permit2Address, "allowance", blockNumber, common.HexToAddress(owner), common.HexToAddress(token), common.HexToAddress(spender))
+	if err != nil {
+		return nil, err
+	}
+
+	var allowance PackedAllowance
+	if allowance.Amount, err = resultAt[*big.Int]("allowance", results, 0); err != nil {
+		return nil, err
+	}
+	if allowance.Expiration, err = resultAt[*big.Int]("allowance", results, 1); err != nil {
+		return nil, err
+	}
+	if allowance.Nonce, err = resultAt[*big.Int]("allowance", results, 2); err != nil {
+		return nil, err
+	}
+	return &allowance, nil
+}
+
+func Permit2NonceBitmap(ctx context.Context, cli *ethclient.Client, owner string, wordPos *big.Int, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, permit2ABI, Permit2Address, "nonceBitmap", blockNumber, common.HexToAddress(owner), wordPos)
+}
+
+func Permit2Approve(ctx context.Context, cli *ethclient.Client, key, token, spender string, amount, expiration *big.Int) (string, error) {
+	data, err := Permit2ApproveData(token, spender, amount, expiration)
+	if err != nil {
+		return "", err
+	}
+	permit2 := Permit2Address
+	return SendLegacyTx(ctx, cli, key, &permit2, "0", BytesToHex(data), "0", 0)
+}
+
+func Permit2Permit(ctx context.Context, cli *ethclient.Client, key, owner string, permit PermitSingle, signature []byte) (string, error) {
+	data, err := Permit2PermitData(owner, permit, signature)
+	if err != nil {
+		return "", err
+	}
+	permit2 := Permit2Address
+	return SendLegacyTx(ctx, cli, key, &permit2, "0", BytesToHex(data), "0", 0)
+}
+
+func Permit2ApproveData(token, spender string, amount, expiration *big.Int) ([]byte, error) {
+	return permit2ABI.Pack("approve", common.HexToAddress(token), common.HexToAddress(spender), amount, expiration)
+}
+
+func Permit2PermitData(owner string, permit PermitSingle, signature []byte) ([]byte, error) {
+	return permit2ABI.Pack("permit", common.HexToAddress(owner), permit, signature)
+}
+
+func Permit2PermitBatchData(owner string, permit PermitBatch, signature []byte) ([]byte, error) {
+	return permit2BatchABI.Pack("permit", common.HexToAddress(owner), permit, signature)
+}
+
+func Permit2TransferFromData(from, to string, amount *big.Int, token string) ([]byte, error) {
+	return permit2ABI.Pack("transferFrom", common.HexToAddress(from), common.HexToAddress(to), amount, common.HexToAddress(token))
+}
+
+func Permit2BatchTransferFromData(transferDetails []AllowanceTransferDetails) ([]byte, error) {
+	return permit2BatchABI.Pack("transferFrom", transferDetails)
+}
+
+func Permit2InvalidateNoncesData(token, spender string, newNonce *big.Int) ([]byte, error) {
+	return permit2ABI.Pack("invalidateNonces", common.HexToAddress(token), common.HexToAddress(spender), newNonce)
+}
+
+func Permit2InvalidateUnorderedNoncesData(wordPos, mask *big.Int) ([]byte, error) {
+	return permit2ABI.Pack("invalidateUnorderedNonces", wordPos, mask)
+}
+
+func Permit2PermitTransferFromData(permit PermitTransferFrom, transferDetails SignatureTransferDetails, owner string, signature []byte) ([]byte, error) {
+	return permit2ABI.Pack("permitTransferFrom", permit, transferDetails, common.HexToAddress(owner), signature)
+}
+
+func Permit2PermitBatchTransferFromData(permit PermitBatchTransferFrom, transferDetails []SignatureTransferDetails, owner string, signature []byte) ([]byte, error) {
+	return permit2BatchABI.Pack("permitTransferFrom", permit, transferDetails, common.HexToAddress(owner), signature)
+}
+
+func Permit2PermitWitnessTransferFromData(permit PermitTransferFrom, transferDetails SignatureTransferDetails, owner string,
+	witness common.Hash, witnessTypeString string, signature []byte) ([]byte, error) {
+	return permit2ABI.Pack("permitWitnessTransferFrom", permit, transferDetails, common.HexToAddress(owner), witness, witnessTypeString, signature)
+}
+
+func Permit2PermitBatchWitnessTransferFromData(permit PermitBatchTransferFrom, transferDetails []SignatureTransferDetails, owner string,
+	witness common.Hash, witnessTypeString string, signature []byte) ([]byte, error) {
+	return permit2BatchABI.Pack("permitWitnessTransferFrom", permit, transferDetails, common.HexToAddress(owner), witness, witnessTypeString, signature)
+}
+
+// PermitSingleTypedData builds the EIP-712 payload signed for Permit2.permit(owner, PermitSingle, signature).
+func PermitSingleTypedData(chainId *big.Int, permit PermitSingle) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": permit2DomainTypes,
+			"PermitSingle": {
+				{Name: "details", Type: "PermitDetails"},
+				{Name: "spender", Type: "address"},
+				{Name: "sigDeadline", Type: "uint256"},
+			},
+			"PermitDetails": permit2DetailsTypes,
+		},
+		PrimaryType: "PermitSingle",
+		Domain:      permit2Domain(chainId),
+		Message: apitypes.TypedDataMessage{
+			"details":     permit2DetailsMessage(permit.Details),
+			"spender":     permit.Spender.Hex(),
+			"sigDeadline": permit.SigDeadline,
+		},
+	}
+}
+
+// PermitBatchTypedData builds the EIP-712 payload signed for the batch variant of Permit2.permit.
+func PermitBatchTypedData(chainId *big.Int, permit PermitBatch) apitypes.TypedData {
+	details := make([]interface{}, 0, len(permit.Details))
+	for _, v := range permit.Details {
+		details = append(details, permit2DetailsMessage(v))
+	}
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": permit2DomainTypes,
+			"PermitBatch": {
+				{Name: "details", Type: "PermitDetails[]"},
+				{Name: "spender", Type: "address"},
+				{Name: "sigDeadline", Type: "uint256"},
+			},
+			"PermitDetails": permit2DetailsTypes,
+		},
+		PrimaryType: "PermitBatch",
+		Domain:      permit2Domain(chainId),
+		Message: apitypes.TypedDataMessage{
+			"details":     details,
+			"spender":     permit.Spender.Hex(),
+			"sigDeadline": permit.SigDeadline,
+		},
+	}
+}
+
+// PermitTransferFromTypedData builds the EIP-712 payload signed for Permit2.permitTransferFrom.
+// A non-nil witness switches the primary type to PermitWitnessTransferFrom.
+func PermitTransferFromTypedData(chainId *big.Int, permit PermitTransferFrom, spender string, witness *Permit2Witness) apitypes.TypedData {
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": permit2DomainTypes,
+			"PermitTransferFrom": {
+				{Name: "permitted", Type: "TokenPermissions"},
+				{Name: "spender", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+			},
+			"TokenPermissions": permit2TokenPermissionsTypes,
+		},
+		PrimaryType: "PermitTransferFrom",
+		Domain:      permit2Domain(chainId),
+		Message: apitypes.TypedDataMessage{
+			"permitted": permit2TokenPermissionsMessage(permit.Permitted),
+			"spender":   common.HexToAddress(spender).Hex(),
+			"nonce":     permit.Nonce,
+			"deadline":  permit.Deadline,
+		},
+	}
+	if witness != nil {
+		permit2AttachWitness(&typedData, "PermitWitnessTransferFrom", witness)
+	}
+	return typedData
+}
+
+// PermitBatchTransferFromTypedData builds the EIP-712 payload signed for the batch variant of
+// Permit2.permitTransferFrom. A non-nil witness switches the primary type to PermitBatchWitnessTransferFrom.
+func PermitBatchTransferFromTypedData(chainId *big.Int, permit PermitBatchTransferFrom, spender string, witness *Permit2Witness) apitypes.TypedData {
+	permitted := make([]interface{}, 0, len(permit.Permitted))
+	for _, v := range permit.Permitted {
+		permitted = append(permitted, permit2TokenPermissionsMessage(v))
+	}
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": permit2DomainTypes,
+			"PermitBatchTransferFrom": {
+				{Name: "permitted", Type: "TokenPermissions[]"},
+				{Name: "spender", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+			},
+			"TokenPermissions": permit2TokenPermissionsTypes,
+		},
+		PrimaryType: "PermitBatchTransferFrom",
+		Domain:      permit2Domain(chainId),
+		Message: apitypes.TypedDataMessage{
+			"permitted": permitted,
+			"spender":   common.HexToAddress(spender).Hex(),
+			"nonce":     permit.Nonce,
+			"deadline":  permit.Deadline,
+		},
+	}
+	if witness != nil {
+		permit2AttachWitness(&typedData, "PermitBatchWitnessTransferFrom", witness)
+	}
+	return typedData
+}
+
+// Permit2WitnessTypeString returns the witnessTypeString argument expected by
+// permitWitnessTransferFrom, i.e. the EIP-712 type encoding following the "witness" member.
+func Permit2WitnessTypeString(witness Permit2Witness) (string, error) {
+	if _, ok := witness.Types[witness.TypeName]; !ok {
+		return "", fmt.Errorf("witness type %q not defined", witness.TypeName)
+	}
+	typedData := PermitTransferFromTypedData(big.NewInt(1), PermitTransferFrom{}, "", &witness)
+	encoded := string(typedData.EncodeType(typedData.PrimaryType))
+	i := strings.Index(encoded, witness.TypeName+" witness)")
+	if i < 0 {
+		return "", fmt.Errorf("witness type %q not found in %s", witness.TypeName, encoded)
+	}
+	return encoded[i:], nil
+}
+
+// Permit2WitnessHash returns the struct hash of the witness, passed as the witness argument
+// of permitWitnessTransferFrom.
+func Permit2WitnessHash(witness Permit2Witness) (common.Hash, error) {
+	typedData := PermitTransferFromTypedData(big.NewInt(1), PermitTransferFrom{}, "", &witness)
+	bz, err := typedData.HashStruct(witness.TypeName, witness.Value)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(bz), nil
+}
+
+func SignPermitSingle(key string, chainId *big.Int, permit PermitSingle) ([]byte, error) {
+	return SignTypedData(key, PermitSingleTypedData(chainId, permit))
+}
+
+func SignPermitBatch(key string, chainId *big.Int, permit PermitBatch) ([]byte, error) {
+	return SignTypedData(key, PermitBatchTypedData(chainId, permit))
+}
+
+func SignPermitTransferFrom(key string, chainId *big.Int, permit PermitTransferFrom, spender string) ([]byte, error) {
+	return SignTypedData(key, PermitTransferFromTypedData(chainId, permit, spender, nil))
+}
+
+func SignPermitBatchTransferFrom(key string, chainId *big.Int, permit PermitBatchTransferFrom, spender string) ([]byte, error) {
+	return SignTypedData(key, PermitBatchTransferFromTypedData(chainId, permit, spender, nil))
+}
+
+func SignPermitWitnessTransferFrom(key string, chainId *big.Int, permit PermitTransferFrom, spender string, witness Permit2Witness) ([]byte, error) {
+	return SignTypedData(key, PermitTransferFromTypedData(chainId, permit, spender, &witness))
+}
+
+func SignPermitBatchWitnessTransferFrom(key string, chainId *big.Int, permit PermitBatchTransferFrom, spender string, witness Permit2Witness) ([]byte, error) {
+	return SignTypedData(key, PermitBatchTransferFromTypedData(chainId, permit, spender, &witness))
+}
+
+func permit2Domain(chainId *big.Int) apitypes.TypedDataDomain {
+	return apitypes.TypedDataDomain{
+		Name:              "Permit2",
+		ChainId:           (*math.HexOrDecimal256)(chainId),
+		VerifyingContract: Permit2Address,
+	}
+}
+
+func permit2DetailsMessage(details PermitDetails) map[string]interface{} {
+	return map[string]interface{}{
+		"token":      details.Token.Hex(),
+		"amount":     details.Amount,
+		"expiration": details.Expiration,
+		"nonce":      details.Nonce,
+	}
+}
+
+func permit2TokenPermissionsMessage(permitted TokenPermissions) map[string]interface{} {
+	return map[string]interface{}{
+		"token":  permitted.Token.Hex(),
+		"amount": permitted.Amount,
+	}
+}
+
+func permit2AttachWitness(typedData *apitypes.TypedData, primaryType string, witness *Permit2Witness) {
+	fields := append([]apitypes.Type{}, typedData.Types[typedData.PrimaryType]...)
+	fields = append(fields, apitypes.Type{Name: "witness", Type: witness.TypeName})
+	delete(typedData.Types, typedData.PrimaryType)
+	typedData.Types[primaryType] = fields
+	for name, types := range witness.Types {
+		typedData.Types[name] = types
+	}
+	typedData.PrimaryType = primaryType
+	typedData.Message["witness"] = witness.Value
+}
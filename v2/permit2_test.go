@@ -0,0 +1,100 @@
+package ethcli
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+var (
+	exampleKey     = "a75208cc60307aa44e8abdc631cdffb43c3bfb70ea7a6155f4f21ae87efb87bc"
+	exampleAddress = common.HexToAddress("0x980fcd34d8cF84AA16462c36D1fCa4920c04e7F9")
+	exampleToken   = common.HexToAddress("0xF557617506209564168E494E67B3E686c560397A")
+)
+
+func Test_PermitSingleTypeHash(t *testing.T) {
+	typedData := PermitSingleTypedData(big.NewInt(1), PermitSingle{})
+	got := common.BytesToHash(typedData.TypeHash(typedData.PrimaryType))
+	want := common.HexToHash("0xf3841cd1ff0085026a6327b620b67997ce40f282c88a8e905a7a5626e310f3d0")
+	if got != want {
+		t.Fatalf("typehash %s, want %s", got.Hex(), want.Hex())
+	}
+}
+
+func Test_SignPermitSingle(t *testing.T) {
+	permit := PermitSingle{
+		Details: PermitDetails{
+			Token:      exampleToken,
+			Amount:     big.NewInt(1000),
+			Expiration: big.NewInt(1700000000),
+			Nonce:      big.NewInt(0),
+		},
+		Spender:     exampleAddress,
+		SigDeadline: big.NewInt(1700000000),
+	}
+	signature, err := SignPermitSingle(exampleKey, big.NewInt(11155111), permit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signature) != 65 || signature[64] < 27 {
+		t.Fatalf("unexpected signature %x", signature)
+	}
+
+	ok, err := VerifyTypedData(PermitSingleTypedData(big.NewInt(11155111), permit), signature, exampleAddress.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("signature does not verify")
+	}
+
+	if _, err := Permit2PermitData(exampleAddress.Hex(), permit, signature); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_Permit2WitnessTypeString(t *testing.T) {
+	witness := Permit2Witness{
+		TypeName: "ExampleTrade",
+		Types: apitypes.Types{
+			"ExampleTrade": {
+				{Name: "exampleTokenAddress", Type: "address"},
+				{Name: "exampleMinimumAmountOut", Type: "uint256"},
+			},
+		},
+		Value: map[string]interface{}{
+			"exampleTokenAddress":     exampleToken.Hex(),
+			"exampleMinimumAmountOut": big.NewInt(1),
+		},
+	}
+	want := "ExampleTrade witness)ExampleTrade(address exampleTokenAddress,uint256 exampleMinimumAmountOut)TokenPermissions(address token,uint256 amount)"
+	typeString, err := Permit2WitnessTypeString(witness)
+	if err != nil || typeString != want {
+		t.Fatalf("got %q %v", typeString, err)
+	}
+	if _, err := Permit2WitnessTypeString(Permit2Witness{TypeName: "Missing"}); err == nil {
+		t.Fatal("expected error for undefined witness type")
+	}
+
+	permit := PermitBatchTransferFrom{
+		Permitted: []TokenPermissions{{Token: exampleToken, Amount: big.NewInt(10)}},
+		Nonce:     big.NewInt(1),
+		Deadline:  big.NewInt(1700000000),
+	}
+	signature, err := SignPermitBatchWitnessTransferFrom(exampleKey, big.NewInt(1), permit, exampleAddress.Hex(), witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	witnessHash, err := Permit2WitnessHash(witness)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = Permit2PermitBatchWitnessTransferFromData(permit,
+		[]SignatureTransferDetails{{To: exampleAddress, RequestedAmount: big.NewInt(10)}},
+		exampleAddress.Hex(), witnessHash, typeString, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
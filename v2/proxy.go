@@ -0,0 +1,186 @@
+package ethcli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const proxyAbi = `[{"inputs":[],"name":"implementation","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"proxiableUUID","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"masterCopy","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"}]`
+
+var (
+	// EIP1967ImplementationSlot is bytes32(uint256(keccak256("eip1967.proxy.implementation")) - 1).
+	EIP1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	// EIP1967AdminSlot is bytes32(uint256(keccak256("eip1967.proxy.admin")) - 1).
+	EIP1967AdminSlot = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+	// EIP1967BeaconSlot is bytes32(uint256(keccak256("eip1967.proxy.beacon")) - 1).
+	EIP1967BeaconSlot = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+	// EIP1822ProxiableSlot is keccak256("PROXIABLE").
+	EIP1822ProxiableSlot = common.HexToHash("0xc5f16f0fcc639fa48a6947836d9850f504798523bf8c9a3a87d5876cf622bcf7")
+
+	// ErrNotProxy is returned by ProxyImplementation when no known proxy pattern matches.
+	ErrNotProxy = errors.New("not a proxy")
+
+	eip1167Prefix = common.FromHex("0x363d3d373d3d3d363d73")
+	eip1167Suffix = common.FromHex("0x5af43d82803e903d91602b57fd5bf3")
+)
+
+type ProxyType int
+
+const (
+	ProxyNone ProxyType = iota
+	// ProxyEIP1967 stores its implementation in EIP1967ImplementationSlot without an admin or UUPS implementation.
+	ProxyEIP1967
+	// ProxyTransparent is an EIP-1967 proxy with an admin.
+	ProxyTransparent
+	// ProxyUUPS is an EIP-1822 proxy, either the original PROXIABLE slot or an EIP-1967 slot whose implementation
+	// answers proxiableUUID.
+	ProxyUUPS
+	// ProxyBeacon reads its implementation from the beacon in EIP1967BeaconSlot.
+	ProxyBeacon
+	// ProxyEIP1167 is a minimal proxy with the implementation embedded in its bytecode.
+	ProxyEIP1167
+	// ProxySafe is a Safe proxy answering masterCopy().
+	ProxySafe
+)
+
+func (t ProxyType) String() string {
+	switch t {
+	case ProxyEIP1967:
+		return "EIP1967"
+	case ProxyTransparent:
+		return "Transparent"
+	case ProxyUUPS:
+		return "UUPS"
+	case ProxyBeacon:
+		return "Beacon"
+	case ProxyEIP1167:
+		return "EIP1167"
+	case ProxySafe:
+		return "Safe"
+	default:
+		return "None"
+	}
+}
+
+// ProxyInfo describes a detected proxy. Admin and Beacon are empty unless the proxy uses them.
+type ProxyInfo struct {
+	Type           ProxyType
+	Implementation string
+	Admin          string
+	Beacon         string
+}
+
+// DetectProxy inspects address at blockNumber and returns the proxy pattern it follows and its implementation.
+// Type is ProxyNone when no known pattern matches. Calls that revert or return undecodable data while
+// probing are treated as not matching; node and transport errors are returned.
+func DetectProxy(ctx context.Context, cli *ethclient.Client, address string, blockNumber *big.Int) (*ProxyInfo, error) {
+	account := common.HexToAddress(address)
+	code, err := cli.CodeAt(ctx, account, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return &ProxyInfo{Type: ProxyNone}, nil
+	}
+
+	if impl, ok := eip1167Implementation(code); ok {
+		return &ProxyInfo{Type: ProxyEIP1167, Implementation: impl.Hex()}, nil
+	}
+
+	impl, err := storageAddress(ctx, cli, account, EIP1967ImplementationSlot, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if impl != (common.Address{}) {
+		info := &ProxyInfo{Type: ProxyEIP1967, Implementation: impl.Hex()}
+		admin, err := storageAddress(ctx, cli, account, EIP1967AdminSlot, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		if admin != (common.Address{}) {
+			info.Type = ProxyTransparent
+			info.Admin = admin.Hex()
+			return info, nil
+		}
+		uuid, err := callResult[[32]byte](ctx, cli, proxyABI, impl.Hex(), "proxiableUUID", blockNumber)
+		if err := ignoreCallFailure(err); err != nil {
+			return nil, err
+		}
+		if err == nil && common.Hash(uuid) == EIP1967ImplementationSlot {
+			info.Type = ProxyUUPS
+		}
+		return info, nil
+	}
+
+	beacon, err := storageAddress(ctx, cli, account, EIP1967BeaconSlot, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if beacon != (common.Address{}) {
+		impl, err := callResult[common.Address](ctx, cli, proxyABI, beacon.Hex(), "implementation", blockNumber)
+		if err == nil {
+			return &ProxyInfo{Type: ProxyBeacon, Implementation: impl.Hex(), Beacon: beacon.Hex()}, nil
+		}
+		if err := ignoreCallFailure(err); err != nil {
+			return nil, err
+		}
+	}
+
+	impl, err = storageAddress(ctx, cli, account, EIP1822ProxiableSlot, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if impl != (common.Address{}) {
+		return &ProxyInfo{Type: ProxyUUPS, Implementation: impl.Hex()}, nil
+	}
+
+	impl, err = callResult[common.Address](ctx, cli, proxyABI, address, "masterCopy", blockNumber)
+	if err := ignoreCallFailure(err); err != nil {
+		return nil, err
+	}
+	if err == nil && impl != (common.Address{}) {
+		implCode, err := cli.CodeAt(ctx, impl, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		if len(implCode) > 0 {
+			return &ProxyInfo{Type: ProxySafe, Implementation: impl.Hex()}, nil
+		}
+	}
+
+	return &ProxyInfo{Type: ProxyNone}, nil
+}
+
+// ProxyImplementation returns the implementation behind the proxy at address, or ErrNotProxy.
+func ProxyImplementation(ctx context.Context, cli *ethclient.Client, address string, blockNumber *big.Int) (string, error) {
+	info, err := DetectProxy(ctx, cli, address, blockNumber)
+	if err != nil {
+		return "", err
+	}
+	if info.Type == ProxyNone {
+		return "", ErrNotProxy
+	}
+	return info.Implementation, nil
+}
+
+// eip1167Implementation extracts the implementation from EIP-1167 minimal proxy runtime code.
+func eip1167Implementation(code []byte) (common.Address, bool) {
+	if len(code) != len(eip1167Prefix)+common.AddressLength+len(eip1167Suffix) ||
+		!bytes.HasPrefix(code, eip1167Prefix) || !bytes.HasSuffix(code, eip1167Suffix) {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(code[len(eip1167Prefix) : len(eip1167Prefix)+common.AddressLength]), true
+}
+
+func storageAddress(ctx context.Context, cli *ethclient.Client, account common.Address, slot common.Hash, blockNumber *big.Int) (common.Address, error) {
+	bz, err := cli.StorageAt(ctx, account, slot, blockNumber)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return common.BytesToAddress(bz), nil
+}
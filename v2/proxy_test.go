@@ -0,0 +1,119 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func Test_DetectProxy(t *testing.T) {
+	var (
+		impl        = common.HexToAddress("0x1000000000000000000000000000000000000001")
+		uupsImpl    = common.HexToAddress("0x1000000000000000000000000000000000000002")
+		admin       = common.HexToAddress("0x2000000000000000000000000000000000000001")
+		beacon      = common.HexToAddress("0x3000000000000000000000000000000000000001")
+		plain       = common.HexToAddress("0x4000000000000000000000000000000000000001")
+		eip1967     = common.HexToAddress("0x4000000000000000000000000000000000000002")
+		transparent = common.HexToAddress("0x4000000000000000000000000000000000000003")
+		uups        = common.HexToAddress("0x4000000000000000000000000000000000000004")
+		beaconProxy = common.HexToAddress("0x4000000000000000000000000000000000000005")
+		legacyUUPS  = common.HexToAddress("0x4000000000000000000000000000000000000006")
+		clone       = common.HexToAddress("0x4000000000000000000000000000000000000007")
+		safe        = common.HexToAddress("0x4000000000000000000000000000000000000008")
+		empty       = common.HexToAddress("0x4000000000000000000000000000000000000009")
+		deadBeacon  = common.HexToAddress("0x3000000000000000000000000000000000000002")
+		broken      = common.HexToAddress("0x400000000000000000000000000000000000000a")
+		flaky       = common.HexToAddress("0x400000000000000000000000000000000000000b")
+	)
+	storage := map[common.Address]map[common.Hash]common.Address{
+		eip1967:     {EIP1967ImplementationSlot: impl},
+		transparent: {EIP1967ImplementationSlot: impl, EIP1967AdminSlot: admin},
+		uups:        {EIP1967ImplementationSlot: uupsImpl},
+		beaconProxy: {EIP1967BeaconSlot: beacon},
+		legacyUUPS:  {EIP1822ProxiableSlot: impl},
+		broken:      {EIP1967BeaconSlot: deadBeacon},
+	}
+	cloneCode := append(append(append([]byte{}, eip1167Prefix...), impl.Bytes()...), eip1167Suffix...)
+
+	rpc := newMockRPC(t, map[string]mockHandler{
+		"eth_getCode": func(params []json.RawMessage) (interface{}, *mockError) {
+			var address common.Address
+			_ = json.Unmarshal(params[0], &address)
+			switch address {
+			case empty:
+				return hexutil.Bytes{}, nil
+			case clone:
+				return hexutil.Bytes(cloneCode), nil
+			}
+			return hexutil.Bytes{0x60, 0x80}, nil
+		},
+		"eth_getStorageAt": func(params []json.RawMessage) (interface{}, *mockError) {
+			var address common.Address
+			var slot common.Hash
+			_ = json.Unmarshal(params[0], &address)
+			_ = json.Unmarshal(params[1], &slot)
+			return common.BytesToHash(storage[address][slot].Bytes()), nil
+		},
+		"eth_call": mockContracts([]string{proxyAbi}, map[string]mockMethod{
+			"proxiableUUID": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				if to != uupsImpl {
+					return nil, mockRevert
+				}
+				return []interface{}{[32]byte(EIP1967ImplementationSlot)}, nil
+			},
+			"implementation": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				if to != beacon {
+					return nil, mockRevert
+				}
+				return []interface{}{impl}, nil
+			},
+			"masterCopy": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				if to == flaky {
+					return nil, &mockError{Code: -32005, Message: "rate limit exceeded"}
+				}
+				if to != safe {
+					return nil, mockRevert
+				}
+				return []interface{}{impl}, nil
+			},
+		}),
+	})
+	cli := rpc.client(t)
+
+	tests := []struct {
+		address common.Address
+		want    ProxyInfo
+	}{
+		{plain, ProxyInfo{Type: ProxyNone}},
+		{empty, ProxyInfo{Type: ProxyNone}},
+		{eip1967, ProxyInfo{Type: ProxyEIP1967, Implementation: impl.Hex()}},
+		{transparent, ProxyInfo{Type: ProxyTransparent, Implementation: impl.Hex(), Admin: admin.Hex()}},
+		{uups, ProxyInfo{Type: ProxyUUPS, Implementation: uupsImpl.Hex()}},
+		{beaconProxy, ProxyInfo{Type: ProxyBeacon, Implementation: impl.Hex(), Beacon: beacon.Hex()}},
+		{legacyUUPS, ProxyInfo{Type: ProxyUUPS, Implementation: impl.Hex()}},
+		{clone, ProxyInfo{Type: ProxyEIP1167, Implementation: impl.Hex()}},
+		{safe, ProxyInfo{Type: ProxySafe, Implementation: impl.Hex()}},
+		// a beacon whose implementation() reverts does not make a beacon proxy
+		{broken, ProxyInfo{Type: ProxyNone}},
+	}
+	for _, tt := range tests {
+		got, err := DetectProxy(context.Background(), cli, tt.address.Hex(), nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.address.Hex(), err)
+		}
+		if *got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.address.Hex(), *got, tt.want)
+		}
+	}
+
+	if _, err := DetectProxy(context.Background(), cli, flaky.Hex(), nil); err == nil {
+		t.Fatal("expected the node error of a probe to be returned")
+	}
+	if _, err := ProxyImplementation(context.Background(), cli, plain.Hex(), nil); !errors.Is(err, ErrNotProxy) {
+		t.Fatalf("got %v, want ErrNotProxy", err)
+	}
+}
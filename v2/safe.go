@@ -0,0 +1,203 @@
+package ethcli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+const (
+	SafeOperationCall         uint8 = 0
+	SafeOperationDelegateCall uint8 = 1
+
+	// SafeMultiSendCallOnlyAddress is the canonical MultiSendCallOnly v1.3.0 deployment.
+	SafeMultiSendCallOnlyAddress = "0x40A2aCCbd92BCA938b02010E17A5b8929b49130D"
+)
+
+var (
+	safeAbi          = `[{"inputs":[{"internalType":"bytes32","name":"hashToApprove","type":"bytes32"}],"name":"approveHash","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"value","type":"uint256"},{"internalType":"bytes","name":"data","type":"bytes"},{"internalType":"uint8","name":"operation","type":"uint8"},{"internalType":"uint256","name":"safeTxGas","type":"uint256"},{"internalType":"uint256","name":"baseGas","type":"uint256"},{"internalType":"uint256","name":"gasPrice","type":"uint256"},{"internalType":"address","name":"gasToken","type":"address"},{"internalType":"address","name":"refundReceiver","type":"address"},{"internalType":"bytes","name":"signatures","type":"bytes"}],"name":"execTransaction","outputs":[{"internalType":"bool","name":"success","type":"bool"}],"stateMutability":"payable","type":"function"},{"inputs":[],"name":"getOwners","outputs":[{"internalType":"address[]","name":"","type":"address[]"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"getThreshold","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"to","type":"address"},{"internalType":"uint256","name":"value","type":"uint256"},{"internalType":"bytes","name":"data","type":"bytes"},{"internalType":"uint8","name":"operation","type":"uint8"},{"internalType":"uint256","name":"safeTxGas","type":"uint256"},{"internalType":"uint256","name":"baseGas","type":"uint256"},{"internalType":"uint256","name":"gasPrice","type":"uint256"},{"internalType":"address","name":"gasToken","type":"address"},{"internalType":"address","name":"refundReceiver","type":"address"},{"internalType":"uint256","name":"_nonce","type":"uint256"}],"name":"getTransactionHash","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"nonce","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+	safeMultiSendAbi = `[{"inputs":[{"internalType":"bytes","name":"transactions","type":"bytes"}],"name":"multiSend","outputs":[],"stateMutability":"payable","type":"function"}]`
+)
+
+// SafeTx is a Safe (Gnosis Safe) multisig transaction.
+type SafeTx struct {
+	To             common.Address
+	Value          *big.Int
+	Data           []byte
+	Operation      uint8
+	SafeTxGas      *big.Int
+	BaseGas        *big.Int
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+	Nonce          *big.Int
+}
+
+// SafeSignature is one owner's signature over a SafeTx hash.
+type SafeSignature struct {
+	Signer common.Address
+	Data   []byte
+}
+
+// NewSafeTx wraps calldata produced by any *Data encoder, e.g. ERC20TransferData, into a SafeTx.
+// nonce is the Safe's current nonce, see SafeNonce.
+func NewSafeTx(to string, value *big.Int, data []byte, nonce *big.Int) *SafeTx {
+	return &SafeTx{
+		To:        common.HexToAddress(to),
+		Value:     bigOrZero(value),
+		Data:      data,
+		Operation: SafeOperationCall,
+		Nonce:     nonce,
+	}
+}
+
+// NewSafeMultiSendTx batches calls into a single SafeTx delegate-calling the MultiSendCallOnly contract at multiSend.
+func NewSafeMultiSendTx(multiSend string, calls []Call, nonce *big.Int) (*SafeTx, error) {
+	data, err := SafeMultiSendData(calls)
+	if err != nil {
+		return nil, err
+	}
+	tx := NewSafeTx(multiSend, nil, data, nonce)
+	tx.Operation = SafeOperationDelegateCall
+	return tx, nil
+}
+
+func SafeNonce(ctx context.Context, cli *ethclient.Client, safe string, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, safeABI, safe, "nonce", blockNumber)
+}
+
+func SafeGetThreshold(ctx context.Context, cli *ethclient.Client, safe string, blockNumber *big.Int) (*big.Int, error) {
+	return callResult[*big.Int](ctx, cli, safeABI, safe, "getThreshold", blockNumber)
+}
+
+func SafeGetOwners(ctx context.Context, cli *ethclient.Client, safe string, blockNumber *big.Int) ([]common.Address, error) {
+	return callResult[[]common.Address](ctx, cli, safeABI, safe, "getOwners", blockNumber)
+}
+
+// SafeTxTypedData builds the EIP-712 payload of tx for the Safe at safe (v1.3.0+ domain).
+func SafeTxTypedData(safe string, chainId *big.Int, tx *SafeTx) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"SafeTx": {
+				{Name: "to", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "data", Type: "bytes"},
+				{Name: "operation", Type: "uint8"},
+				{Name: "safeTxGas", Type: "uint256"},
+				{Name: "baseGas", Type: "uint256"},
+				{Name: "gasPrice", Type: "uint256"},
+				{Name: "gasToken", Type: "address"},
+				{Name: "refundReceiver", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+			},
+		},
+		PrimaryType: "SafeTx",
+		Domain: apitypes.TypedDataDomain{
+			ChainId:           (*math.HexOrDecimal256)(chainId),
+			VerifyingContract: common.HexToAddress(safe).Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"to":             tx.To.Hex(),
+			"value":          bigOrZero(tx.Value),
+			"data":           hexutil.Bytes(tx.Data),
+			"operation":      big.NewInt(int64(tx.Operation)),
+			"safeTxGas":      bigOrZero(tx.SafeTxGas),
+			"baseGas":        bigOrZero(tx.BaseGas),
+			"gasPrice":       bigOrZero(tx.GasPrice),
+			"gasToken":       tx.GasToken.Hex(),
+			"refundReceiver": tx.RefundReceiver.Hex(),
+			"nonce":          bigOrZero(tx.Nonce),
+		},
+	}
+}
+
+// SafeTxHash returns the hash owners sign, equal to Safe.getTransactionHash.
+func SafeTxHash(safe string, chainId *big.Int, tx *SafeTx) (common.Hash, error) {
+	return TypedDataHash(SafeTxTypedData(safe, chainId, tx))
+}
+
+// SignSafeTx signs tx as an owner of safe.
+func SignSafeTx(key string, safe string, chainId *big.Int, tx *SafeTx) (SafeSignature, error) {
+	typedData := SafeTxTypedData(safe, chainId, tx)
+	signature, err := SignTypedData(key, typedData)
+	if err != nil {
+		return SafeSignature{}, err
+	}
+	signer, err := RecoverTypedDataSigner(typedData, signature)
+	if err != nil {
+		return SafeSignature{}, err
+	}
+	return SafeSignature{Signer: signer, Data: signature}, nil
+}
+
+// SafeApprovedHashSignature is the pre-validated signature of an owner that called approveHash,
+// or that submits execTransaction itself.
+func SafeApprovedHashSignature(owner string) SafeSignature {
+	data := make([]byte, 65)
+	copy(data[12:32], common.HexToAddress(owner).Bytes())
+	data[64] = 1
+	return SafeSignature{Signer: common.HexToAddress(owner), Data: data}
+}
+
+// SafeSignatures concatenates owner signatures sorted by signer address, as execTransaction requires.
+func SafeSignatures(signatures []SafeSignature) ([]byte, error) {
+	sorted := make([]SafeSignature, len(signatures))
+	copy(sorted, signatures)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].Signer.Bytes(), sorted[j].Signer.Bytes()) < 0
+	})
+	var bz []byte
+	for i, v := range sorted {
+		if len(v.Data) != 65 {
+			return nil, errors.New("invalid signature length for " + v.Signer.Hex())
+		}
+		if i > 0 && v.Signer == sorted[i-1].Signer {
+			return nil, errors.New("duplicate signature for " + v.Signer.Hex())
+		}
+		bz = append(bz, v.Data...)
+	}
+	return bz, nil
+}
+
+func SafeExecTransaction(ctx context.Context, cli *ethclient.Client, key string, safe string, tx *SafeTx, signatures []byte) (string, error) {
+	data, err := SafeExecTransactionData(tx, signatures)
+	if err != nil {
+		return "", err
+	}
+	return SendLegacyTx(ctx, cli, key, &safe, "0", BytesToHex(data), "0", 0)
+}
+
+func SafeExecTransactionData(tx *SafeTx, signatures []byte) ([]byte, error) {
+	return safeABI.Pack("execTransaction", tx.To, bigOrZero(tx.Value), tx.Data, tx.Operation,
+		bigOrZero(tx.SafeTxGas), bigOrZero(tx.BaseGas), bigOrZero(tx.GasPrice), tx.GasToken, tx.RefundReceiver, signatures)
+}
+
+func SafeApproveHashData(hash common.Hash) ([]byte, error) {
+	return safeABI.Pack("approveHash", hash)
+}
+
+// SafeMultiSendData encodes multiSend(transactions) where every call is packed as
+// operation(uint8) ‖ to(address) ‖ value(uint256) ‖ len(data)(uint256) ‖ data.
+func SafeMultiSendData(calls []Call) ([]byte, error) {
+	var transactions []byte
+	for _, v := range calls {
+		transactions = append(transactions, SafeOperationCall)
+		transactions = append(transactions, v.To.Bytes()...)
+		transactions = append(transactions, uint256Word(v.Value)...)
+		transactions = append(transactions, uint256Word(big.NewInt(int64(len(v.Data))))...)
+		transactions = append(transactions, v.Data...)
+	}
+	return safeMultiSendABI.Pack("multiSend", transactions)
+}
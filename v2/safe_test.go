@@ -0,0 +1,110 @@
+package ethcli
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func Test_SafeTxTypeHash(t *testing.T) {
+	typedData := SafeTxTypedData(exampleAddress.Hex(), big.NewInt(1), &SafeTx{})
+	if got := common.BytesToHash(typedData.TypeHash("SafeTx")); got != common.HexToHash("0xbb8310d486368db6bd6f849402fdd73ad53d316b5a4b2644ad6efe0f941286d8") {
+		t.Fatalf("SafeTx typehash %s", got.Hex())
+	}
+	if got := common.BytesToHash(typedData.TypeHash("EIP712Domain")); got != common.HexToHash("0x47e79534a245952e8b16893a336b85a3d9ea9fa8c573f3d803afb92a79469218") {
+		t.Fatalf("domain typehash %s", got.Hex())
+	}
+}
+
+func Test_SafeSignatures(t *testing.T) {
+	safe := "0x4444444444444444444444444444444444444444"
+	transfer, err := ERC20TransferData(exampleAddress.Hex(), "1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := NewSafeTx(exampleToken.Hex(), nil, transfer, big.NewInt(3))
+	hash, err := SafeTxHash(safe, big.NewInt(1), tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sigs []SafeSignature
+	for i := 0; i < 3; i++ {
+		key, _ := crypto.GenerateKey()
+		sig, err := SignSafeTx(common.Bytes2Hex(crypto.FromECDSA(key)), safe, big.NewInt(1), tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig.Signer != crypto.PubkeyToAddress(key.PublicKey) {
+			t.Fatal("unexpected signer")
+		}
+		sigs = append(sigs, sig)
+	}
+	sigs = append(sigs, SafeApprovedHashSignature(exampleAddress.Hex()))
+
+	packed, err := SafeSignatures(sigs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packed) != 65*len(sigs) {
+		t.Fatalf("unexpected length %d", len(packed))
+	}
+	var prev common.Address
+	for i := 0; i < len(sigs); i++ {
+		chunk := packed[i*65 : (i+1)*65]
+		var owner common.Address
+		if chunk[64] == 1 {
+			owner = common.BytesToAddress(chunk[12:32])
+		} else {
+			owner, err = RecoverAddress(hash.Bytes(), chunk)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if bytes.Compare(owner.Bytes(), prev.Bytes()) <= 0 {
+			t.Fatalf("signatures not sorted at %d", i)
+		}
+		prev = owner
+	}
+
+	if _, err := SafeSignatures(append(sigs, sigs[0])); err == nil {
+		t.Fatal("expected duplicate signer error")
+	}
+	if _, err := SafeExecTransactionData(tx, packed); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_SafeMultiSendData(t *testing.T) {
+	approve, _ := ERC20ApproveData(exampleAddress.Hex(), "1")
+	transfer, _ := ERC20TransferData(exampleAddress.Hex(), "1")
+	tx, err := NewSafeMultiSendTx(SafeMultiSendCallOnlyAddress, []Call{
+		{To: exampleToken, Data: approve},
+		{To: exampleAddress, Value: big.NewInt(5)},
+		{To: exampleToken, Data: transfer},
+	}, big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Operation != SafeOperationDelegateCall {
+		t.Fatal("multisend must be a delegatecall")
+	}
+	ins, _ := abi.JSON(strings.NewReader(safeMultiSendAbi))
+	args, err := ins.Methods["multiSend"].Inputs.Unpack(tx.Data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	packed := args[0].([]byte)
+	if len(packed) != 3*85+len(approve)+len(transfer) {
+		t.Fatalf("unexpected packed length %d", len(packed))
+	}
+	second := packed[85+len(approve):]
+	if second[0] != SafeOperationCall || common.BytesToAddress(second[1:21]) != exampleAddress || new(big.Int).SetBytes(second[21:53]).Int64() != 5 {
+		t.Fatalf("unexpected second call %x", second[:85])
+	}
+}
@@ -0,0 +1,255 @@
+package ethcli
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	siweHeaderSuffix = " wants you to sign in with your Ethereum account:"
+	siweNonceChars   = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+)
+
+var (
+	ErrSiweInvalidMessage  = errors.New("siwe: invalid message")
+	ErrSiweDomainMismatch  = errors.New("siwe: domain mismatch")
+	ErrSiweNonceMismatch   = errors.New("siwe: nonce mismatch")
+	ErrSiweChainIdMismatch = errors.New("siwe: chain id mismatch")
+	ErrSiweExpired         = errors.New("siwe: message expired")
+	ErrSiweNotYetValid     = errors.New("siwe: message not yet valid")
+	ErrSiweInvalidSigner   = errors.New("siwe: signature does not match address")
+)
+
+// SiweMessage is an EIP-4361 Sign-In with Ethereum message.
+// Zero-valued optional fields are omitted from the rendered message.
+type SiweMessage struct {
+	Scheme         string
+	Domain         string
+	Address        common.Address
+	Statement      string
+	URI            string
+	Version        string
+	ChainId        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime time.Time
+	NotBefore      time.Time
+	RequestId      string
+	Resources      []string
+}
+
+// SiweVerifyOptions lists the expectations checked by VerifySiwe.
+// Empty Domain, Nonce and zero ChainId skip the respective check; a zero Time means time.Now().
+type SiweVerifyOptions struct {
+	Domain  string
+	Nonce   string
+	ChainId int64
+	Time    time.Time
+}
+
+// NewSiweNonce returns a random alphanumeric nonce suitable for SiweMessage.Nonce.
+func NewSiweNonce() (string, error) {
+	buff := make([]byte, 17)
+	max := big.NewInt(int64(len(siweNonceChars)))
+	for i := range buff {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		buff[i] = siweNonceChars[n.Int64()]
+	}
+	return string(buff), nil
+}
+
+// String renders the message in the EIP-4361 text format that is signed with personal_sign.
+func (m *SiweMessage) String() string {
+	var sb strings.Builder
+	if m.Scheme != "" {
+		sb.WriteString(m.Scheme + "://")
+	}
+	sb.WriteString(m.Domain + siweHeaderSuffix + "\n")
+	sb.WriteString(m.Address.Hex() + "\n\n")
+	if m.Statement != "" {
+		sb.WriteString(m.Statement + "\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString("URI: " + m.URI + "\n")
+	sb.WriteString("Version: " + m.Version + "\n")
+	sb.WriteString("Chain ID: " + strconv.FormatInt(m.ChainId, 10) + "\n")
+	sb.WriteString("Nonce: " + m.Nonce + "\n")
+	sb.WriteString("Issued At: " + m.IssuedAt.UTC().Format(time.RFC3339))
+	if !m.ExpirationTime.IsZero() {
+		sb.WriteString("\nExpiration Time: " + m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	if !m.NotBefore.IsZero() {
+		sb.WriteString("\nNot Before: " + m.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if m.RequestId != "" {
+		sb.WriteString("\nRequest ID: " + m.RequestId)
+	}
+	if len(m.Resources) > 0 {
+		sb.WriteString("\nResources:")
+		for _, v := range m.Resources {
+			sb.WriteString("\n- " + v)
+		}
+	}
+	return sb.String()
+}
+
+// ParseSiweMessage parses an EIP-4361 message.
+func ParseSiweMessage(message string) (*SiweMessage, error) {
+	lines := strings.Split(message, "\n")
+	if len(lines) < 8 {
+		return nil, ErrSiweInvalidMessage
+	}
+
+	m := &SiweMessage{}
+	header, ok := strings.CutSuffix(lines[0], siweHeaderSuffix)
+	if !ok {
+		return nil, fmt.Errorf("%w: bad header", ErrSiweInvalidMessage)
+	}
+	if scheme, domain, found := strings.Cut(header, "://"); found {
+		m.Scheme, m.Domain = scheme, domain
+	} else {
+		m.Domain = header
+	}
+	if m.Domain == "" {
+		return nil, fmt.Errorf("%w: empty domain", ErrSiweInvalidMessage)
+	}
+
+	if !common.IsHexAddress(lines[1]) || common.HexToAddress(lines[1]).Hex() != lines[1] {
+		return nil, fmt.Errorf("%w: address must be EIP-55 checksummed", ErrSiweInvalidMessage)
+	}
+	m.Address = common.HexToAddress(lines[1])
+
+	if lines[2] != "" {
+		return nil, fmt.Errorf("%w: missing blank line after address", ErrSiweInvalidMessage)
+	}
+	i := 3
+	if lines[i] != "" {
+		m.Statement = lines[i]
+		i++
+	}
+	if lines[i] != "" {
+		return nil, fmt.Errorf("%w: missing blank line after statement", ErrSiweInvalidMessage)
+	}
+	i++
+
+	fields := lines[i:]
+	next := func(tag string, required bool) (string, error) {
+		if len(fields) > 0 && strings.HasPrefix(fields[0], tag+": ") {
+			v := strings.TrimPrefix(fields[0], tag+": ")
+			fields = fields[1:]
+			return v, nil
+		}
+		if required {
+			return "", fmt.Errorf("%w: missing %s", ErrSiweInvalidMessage, tag)
+		}
+		return "", nil
+	}
+	parseTime := func(tag string, required bool) (time.Time, error) {
+		v, err := next(tag, required)
+		if err != nil || v == "" {
+			return time.Time{}, err
+		}
+		tm, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%w: bad %s", ErrSiweInvalidMessage, tag)
+		}
+		return tm, nil
+	}
+
+	var err error
+	if m.URI, err = next("URI", true); err != nil {
+		return nil, err
+	}
+	if m.Version, err = next("Version", true); err != nil {
+		return nil, err
+	}
+	if m.Version != "1" {
+		return nil, fmt.Errorf("%w: unsupported version %s", ErrSiweInvalidMessage, m.Version)
+	}
+	chainId, err := next("Chain ID", true)
+	if err != nil {
+		return nil, err
+	}
+	if m.ChainId, err = strconv.ParseInt(chainId, 10, 64); err != nil {
+		return nil, fmt.Errorf("%w: bad Chain ID", ErrSiweInvalidMessage)
+	}
+	if m.Nonce, err = next("Nonce", true); err != nil {
+		return nil, err
+	}
+	if len(m.Nonce) < 8 {
+		return nil, fmt.Errorf("%w: nonce too short", ErrSiweInvalidMessage)
+	}
+	if m.IssuedAt, err = parseTime("Issued At", true); err != nil {
+		return nil, err
+	}
+	if m.ExpirationTime, err = parseTime("Expiration Time", false); err != nil {
+		return nil, err
+	}
+	if m.NotBefore, err = parseTime("Not Before", false); err != nil {
+		return nil, err
+	}
+	if m.RequestId, err = next("Request ID", false); err != nil {
+		return nil, err
+	}
+	if len(fields) > 0 && fields[0] == "Resources:" {
+		for _, v := range fields[1:] {
+			resource, ok := strings.CutPrefix(v, "- ")
+			if !ok {
+				return nil, fmt.Errorf("%w: bad resource %q", ErrSiweInvalidMessage, v)
+			}
+			m.Resources = append(m.Resources, resource)
+		}
+		fields = nil
+	}
+	if len(fields) > 0 {
+		return nil, fmt.Errorf("%w: unexpected line %q", ErrSiweInvalidMessage, fields[0])
+	}
+	return m, nil
+}
+
+// VerifySiwe parses message, checks it against opts and verifies that signature is a
+// personal_sign of message by the address it names.
+func VerifySiwe(message string, signature []byte, opts SiweVerifyOptions) (*SiweMessage, error) {
+	m, err := ParseSiweMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Domain != "" && m.Domain != opts.Domain {
+		return nil, ErrSiweDomainMismatch
+	}
+	if opts.Nonce != "" && m.Nonce != opts.Nonce {
+		return nil, ErrSiweNonceMismatch
+	}
+	if opts.ChainId != 0 && m.ChainId != opts.ChainId {
+		return nil, ErrSiweChainIdMismatch
+	}
+	now := opts.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if !m.ExpirationTime.IsZero() && !now.Before(m.ExpirationTime) {
+		return nil, ErrSiweExpired
+	}
+	if !m.NotBefore.IsZero() && now.Before(m.NotBefore) {
+		return nil, ErrSiweNotYetValid
+	}
+
+	signer, err := RecoverPersonalSigner([]byte(message), signature)
+	if err != nil {
+		return nil, err
+	}
+	if signer != m.Address {
+		return nil, ErrSiweInvalidSigner
+	}
+	return m, nil
+}
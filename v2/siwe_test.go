@@ -0,0 +1,92 @@
+package ethcli
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+const exampleSiweMessage = `example.com wants you to sign in with your Ethereum account:
+0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2
+
+I accept the ExampleOrg Terms of Service: https://example.com/tos
+
+URI: https://example.com/login
+Version: 1
+Chain ID: 1
+Nonce: 32891756
+Issued At: 2021-09-30T16:25:24Z
+Resources:
+- ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq/
+- https://example.com/my-web2-claim.json`
+
+func Test_ParseSiweMessage(t *testing.T) {
+	m, err := ParseSiweMessage(exampleSiweMessage)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Domain != "example.com" || m.ChainId != 1 || m.Nonce != "32891756" || len(m.Resources) != 2 {
+		t.Fatalf("unexpected message %+v", m)
+	}
+	if m.String() != exampleSiweMessage {
+		t.Fatalf("round trip mismatch:\n%s", m.String())
+	}
+
+	m.Statement = ""
+	if _, err := ParseSiweMessage(m.String()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func Test_VerifySiwe(t *testing.T) {
+	nonce, err := NewSiweNonce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := SiweMessage{
+		Domain:         "example.com",
+		Address:        exampleAddress,
+		Statement:      "Sign in to Example",
+		URI:            "https://example.com/login",
+		Version:        "1",
+		ChainId:        11155111,
+		Nonce:          nonce,
+		IssuedAt:       issuedAt,
+		ExpirationTime: issuedAt.Add(time.Hour),
+	}
+	message := m.String()
+	signature, err := PersonalSign(exampleKey, []byte(message))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := SiweVerifyOptions{Domain: "example.com", Nonce: nonce, ChainId: 11155111, Time: issuedAt.Add(time.Minute)}
+	if _, err := VerifySiwe(message, signature, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	compact, err := CompactSignature(signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifySiwe(message, compact, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	expired := opts
+	expired.Time = issuedAt.Add(2 * time.Hour)
+	if _, err := VerifySiwe(message, signature, expired); !errors.Is(err, ErrSiweExpired) {
+		t.Fatalf("expected expiry error, got %v", err)
+	}
+	wrongChain := opts
+	wrongChain.ChainId = 1
+	if _, err := VerifySiwe(message, signature, wrongChain); !errors.Is(err, ErrSiweChainIdMismatch) {
+		t.Fatalf("expected chain id error, got %v", err)
+	}
+	wrongDomain := opts
+	wrongDomain.Domain = "evil.com"
+	if _, err := VerifySiwe(message, signature, wrongDomain); !errors.Is(err, ErrSiweDomainMismatch) {
+		t.Fatalf("expected domain error, got %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Holder is one balance of a snapshot. Id is only set for ERC1155; ERC721 balances count tokens.
+type Holder struct {
+	Address common.Address
+	Id      *big.Int
+	Balance *big.Int
+}
+
+// Snapshot lists the holders of a token at a block, largest balance first.
+type Snapshot struct {
+	Token       common.Address
+	Standard    TokenStandard
+	BlockNumber uint64
+	Holders     []Holder
+}
+
+// TakeSnapshot collects every address that received the token since its deployment from the Transfer
+// logs between fromBlock and blockNumber (nil for the head), then reads their balances at blockNumber
+// with batched balanceOf calls. Holders with a zero balance are left out. A nil fromBlock is replaced
+// by the deployment block found with DeploymentBlock; pass it explicitly when the node has no
+// historical state. For ERC1155 ids restricts the snapshot to some ids, nil takes every id seen.
+func TakeSnapshot(ctx context.Context, cli *ethclient.Client, token string, standard TokenStandard, ids []*big.Int, fromBlock, blockNumber *big.Int) (*Snapshot, error) {
+	if blockNumber == nil {
+		head, err := cli.BlockNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		blockNumber = new(big.Int).SetUint64(head)
+	}
+	if fromBlock == nil {
+		deployed, err := DeploymentBlock(ctx, cli, token, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		fromBlock = deployed
+	}
+
+	var ins *abi.ABI
+	switch standard {
+	case StandardERC20:
+		ins = erc20ABI
+	case StandardERC721:
+		ins = erc721ABI
+	case StandardERC1155:
+		ins = erc1155ABI
+	default:
+		return nil, fmt.Errorf("unsupported token standard %s", standard)
+	}
+
+	query := ethereum.FilterQuery{Addresses: []common.Address{common.HexToAddress(token)}}
+	decode := func(log types.Log) ([]Holder, error) {
+		if len(log.Topics) < 3 {
+			return nil, nil
+		}
+		return []Holder{{Address: HashToAddress(log.Topics[2])}}, nil
+	}
+	if standard == StandardERC1155 {
+		query.Topics = [][]common.Hash{{ins.Events["TransferSingle"].ID, ins.Events["TransferBatch"].ID}}
+		wanted := map[string]bool{}
+		for _, id := range ids {
+			wanted[id.String()] = true
+		}
+		decode = func(log types.Log) ([]Holder, error) {
+			events, err := decodeERC1155Transfer(log)
+			if err != nil {
+				return nil, err
+			}
+			var holders []Holder
+			for _, ev := range flattenERC1155Transfers(events) {
+				if len(wanted) == 0 || wanted[ev.Ids[0].String()] {
+					holders = append(holders, Holder{Address: ev.To, Id: ev.Ids[0]})
+				}
+			}
+			return holders, nil
+		}
+	} else {
+		query.Topics = [][]common.Hash{{ins.Events["Transfer"].ID}}
+	}
+
+	seen := map[string]bool{}
+	var holders []Holder
+	err := NewLogScanner(cli, query).Scan(ctx, fromBlock, blockNumber, func(from, to uint64, logs []types.Log) error {
+		for _, log := range logs {
+			decoded, err := decode(log)
+			if err != nil {
+				return err
+			}
+			for _, h := range decoded {
+				key := h.Address.Hex() + idKey(h.Id)
+				if h.Address == (common.Address{}) || seen[key] {
+					continue
+				}
+				seen[key] = true
+				holders = append(holders, h)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	contract := common.HexToAddress(token)
+	msgs := make([]ethereum.CallMsg, 0, len(holders))
+	for _, h := range holders {
+		var data []byte
+		if standard == StandardERC1155 {
+			data, err = ins.Pack("balanceOf", h.Address, h.Id)
+		} else {
+			data, err = ins.Pack("balanceOf", h.Address)
+		}
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, ethereum.CallMsg{To: &contract, Data: data})
+	}
+	results, err := BatchCallContract(ctx, cli, msgs, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{Token: contract, Standard: standard, BlockNumber: blockNumber.Uint64()}
+	for i, r := range results {
+		if r.Err != nil {
+			return nil, fmt.Errorf("balanceOf %s: %w", holders[i].Address.Hex(), r.Err)
+		}
+		values, err := ins.Unpack("balanceOf", r.Data)
+		if err != nil {
+			return nil, err
+		}
+		balance, err := resultAt[*big.Int]("balanceOf", values, 0)
+		if err != nil {
+			return nil, err
+		}
+		if balance.Sign() > 0 {
+			holders[i].Balance = balance
+			snapshot.Holders = append(snapshot.Holders, holders[i])
+		}
+	}
+	sort.Slice(snapshot.Holders, func(i, j int) bool {
+		return lessHolder(snapshot.Holders[i], snapshot.Holders[j])
+	})
+	return snapshot, nil
+}
+
+// lessHolder orders holders by descending balance, then by address and id, a nil id first.
+func lessHolder(a, b Holder) bool {
+	if c := a.Balance.Cmp(b.Balance); c != 0 {
+		return c > 0
+	}
+	if c := a.Address.Cmp(b.Address); c != 0 {
+		return c < 0
+	}
+	if a.Id == nil || b.Id == nil {
+		return a.Id == nil && b.Id != nil
+	}
+	return a.Id.Cmp(b.Id) < 0
+}
+
+// WriteCSV writes one address,balance row per holder, with an id column for ERC1155.
+func (s *Snapshot) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{"address", "balance"}
+	if s.Standard == StandardERC1155 {
+		header = []string{"address", "id", "balance"}
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, h := range s.Holders {
+		row := []string{h.Address.Hex(), h.Balance.String()}
+		if s.Standard == StandardERC1155 {
+			row = []string{h.Address.Hex(), h.Id.String(), h.Balance.String()}
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+type snapshotHolderJSON struct {
+	Address common.Address `json:"address"`
+	Id      string         `json:"id,omitempty"`
+	Balance string         `json:"balance"`
+}
+
+type snapshotJSON struct {
+	Token       common.Address       `json:"token"`
+	Standard    string               `json:"standard"`
+	BlockNumber uint64               `json:"blockNumber"`
+	Holders     []snapshotHolderJSON `json:"holders"`
+}
+
+// WriteJSON writes the snapshot as JSON with balances as decimal strings.
+func (s *Snapshot) WriteJSON(w io.Writer) error {
+	out := snapshotJSON{
+		Token:       s.Token,
+		Standard:    s.Standard.String(),
+		BlockNumber: s.BlockNumber,
+		Holders:     make([]snapshotHolderJSON, 0, len(s.Holders)),
+	}
+	for _, h := range s.Holders {
+		row := snapshotHolderJSON{Address: h.Address, Balance: h.Balance.String()}
+		if h.Id != nil {
+			row.Id = h.Id.String()
+		}
+		out.Holders = append(out.Holders, row)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
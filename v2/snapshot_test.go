@@ -0,0 +1,130 @@
+package ethcli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func Test_TakeSnapshot(t *testing.T) {
+	var (
+		alice = common.HexToAddress("0x1111111111111111111111111111111111111111")
+		bob   = common.HexToAddress("0x2222222222222222222222222222222222222222")
+		carol = common.HexToAddress("0x3333333333333333333333333333333333333333")
+	)
+	balances := map[common.Address]int64{alice: 5, bob: 50, carol: 0}
+	var callBlock string
+	fromBlock := hexutil.Uint64(1 << 63)
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_blockNumber": func(params []json.RawMessage) (interface{}, *mockError) {
+			return "0x64", nil
+		},
+		"eth_getCode": deployedAt(42),
+		"eth_getLogs": func(params []json.RawMessage) (interface{}, *mockError) {
+			var q struct {
+				FromBlock hexutil.Uint64 `json:"fromBlock"`
+			}
+			_ = json.Unmarshal(params[0], &q)
+			fromBlock = min(fromBlock, q.FromBlock)
+			var logs []types.Log
+			for _, to := range []common.Address{alice, bob, carol, alice} {
+				logs = append(logs, types.Log{Address: exampleToken, Topics: []common.Hash{transferTopic, {}, addressTopic(to)}, Data: make([]byte, 32)})
+			}
+			return logs, nil
+		},
+		"eth_call": func(params []json.RawMessage) (interface{}, *mockError) {
+			_ = json.Unmarshal(params[1], &callBlock)
+			return mockContracts([]string{openzeppelinERC20Abi}, map[string]mockMethod{
+				"balanceOf": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+					return []interface{}{big.NewInt(balances[args[0].(common.Address)])}, nil
+				},
+			})(params)
+		},
+	})
+
+	snapshot, err := TakeSnapshot(context.Background(), mock.client(t), exampleToken.Hex(), StandardERC20, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if callBlock != "0x64" || snapshot.BlockNumber != 100 {
+		t.Fatalf("balances not read at the snapshot block: %s", callBlock)
+	}
+	if fromBlock != 42 {
+		t.Fatalf("logs scanned from block %d, want the deployment block", fromBlock)
+	}
+	if mock.count("eth_call") != 3 {
+		t.Fatalf("expected one balanceOf per holder, got %d", mock.count("eth_call"))
+	}
+
+	var buf bytes.Buffer
+	if err := snapshot.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	expected := "address,balance\n" + bob.Hex() + ",50\n" + alice.Hex() + ",5\n"
+	if buf.String() != expected {
+		t.Fatalf("unexpected csv\n%s", buf.String())
+	}
+
+	buf.Reset()
+	if err := snapshot.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var out struct {
+		Standard string `json:"standard"`
+		Holders  []struct {
+			Address common.Address `json:"address"`
+			Balance string         `json:"balance"`
+		} `json:"holders"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Standard != "ERC20" || len(out.Holders) != 2 || out.Holders[0].Address != bob || out.Holders[0].Balance != "50" {
+		t.Fatalf("unexpected json %s", buf.String())
+	}
+}
+
+func Test_TakeSnapshotERC1155(t *testing.T) {
+	ins, _ := abi.JSON(strings.NewReader(openzeppelinIERC1155Abi))
+	batch, _ := ins.Events["TransferBatch"].Inputs.NonIndexed().Pack(
+		[]*big.Int{big.NewInt(1), big.NewInt(2)}, []*big.Int{big.NewInt(5), big.NewInt(7)})
+	mock := newMockRPC(t, map[string]mockHandler{
+		"eth_getLogs": func(params []json.RawMessage) (interface{}, *mockError) {
+			return []types.Log{{Address: exampleToken, Topics: []common.Hash{ins.Events["TransferBatch"].ID, {}, {}, addressTopic(exampleAddress)}, Data: batch}}, nil
+		},
+		"eth_call": mockContracts([]string{openzeppelinIERC1155Abi}, map[string]mockMethod{
+			"balanceOf": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return []interface{}{new(big.Int).Mul(args[1].(*big.Int), big.NewInt(10))}, nil
+			},
+		}),
+	})
+
+	snapshot, err := TakeSnapshot(context.Background(), mock.client(t), exampleToken.Hex(), StandardERC1155, []*big.Int{big.NewInt(2)}, big.NewInt(0), big.NewInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	_ = snapshot.WriteCSV(&buf)
+	if buf.String() != "address,id,balance\n"+exampleAddress.Hex()+",2,20\n" {
+		t.Fatalf("unexpected csv\n%s", buf.String())
+	}
+}
+
+func Test_LessHolder(t *testing.T) {
+	withId := Holder{Address: exampleAddress, Id: big.NewInt(1), Balance: big.NewInt(5)}
+	withoutId := Holder{Address: exampleAddress, Balance: big.NewInt(5)}
+	if !lessHolder(withoutId, withId) || lessHolder(withId, withoutId) {
+		t.Fatal("a nil id should sort first")
+	}
+	if lessHolder(withoutId, withoutId) {
+		t.Fatal("a holder is not less than itself")
+	}
+}
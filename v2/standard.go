@@ -0,0 +1,165 @@
+package ethcli
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const standardProbeAbi = `[{"inputs":[],"name":"asset","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"totalAssets","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"DOMAIN_SEPARATOR","outputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"address","name":"owner","type":"address"}],"name":"nonces","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+
+type TokenStandard int
+
+const (
+	StandardUnknown TokenStandard = iota
+	StandardERC20
+	StandardERC721
+	StandardERC1155
+)
+
+func (s TokenStandard) String() string {
+	switch s {
+	case StandardERC20:
+		return "ERC20"
+	case StandardERC721:
+		return "ERC721"
+	case StandardERC1155:
+		return "ERC1155"
+	}
+	return "unknown"
+}
+
+// StandardInfo is the result of DetectStandard. Metadata and Enumerable refer to the extensions of the
+// detected standard: ERC721Metadata/ERC1155MetadataURI or name/symbol/decimals for ERC20.
+type StandardInfo struct {
+	Standard   TokenStandard
+	HasCode    bool
+	ERC165     bool
+	Metadata   bool
+	Enumerable bool
+	Royalty    bool // ERC2981
+	ERC4906    bool // metadata update events
+	ERC5192    bool // soulbound
+	ERC4626    bool // tokenized vault
+	Permit     bool // ERC2612
+}
+
+// probeCall is one call of a detection batch.
+type probeCall struct {
+	ins    *abi.ABI
+	method string
+	args   []interface{}
+}
+
+// DetectStandard reports which token standard the contract at address implements and which extensions it supports.
+// NFTs are detected through ERC165, which must also reject the invalid id 0xffffffff; other contracts are
+// probed for the ERC20, ERC4626 and ERC2612 methods. Calls that revert count as unsupported.
+func DetectStandard(ctx context.Context, cli *ethclient.Client, address string, blockNumber *big.Int) (*StandardInfo, error) {
+	info := &StandardInfo{}
+	code, err := cli.CodeAt(ctx, common.HexToAddress(address), blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) == 0 {
+		return info, nil
+	}
+	info.HasCode = true
+
+	info.ERC165, err = SupportsERC165(ctx, cli, address, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if info.ERC165 {
+		ids := [][4]byte{InterfaceIdERC721, InterfaceIdERC721Metadata, InterfaceIdERC721Enumerable, InterfaceIdERC1155,
+			InterfaceIdERC1155MetadataURI, InterfaceIdERC2981, InterfaceIdERC4906, InterfaceIdERC5192}
+		calls := make([]probeCall, len(ids))
+		for i, id := range ids {
+			calls[i] = probeCall{erc165ABI, "supportsInterface", []interface{}{id}}
+		}
+		results, err := probeContract(ctx, cli, address, calls, blockNumber)
+		if err != nil {
+			return nil, err
+		}
+		supported := make([]bool, len(results))
+		for i, result := range results {
+			supported[i] = result != nil && result[0] == true
+		}
+		switch {
+		case supported[0]:
+			info.Standard = StandardERC721
+			info.Metadata = supported[1]
+			info.Enumerable = supported[2]
+		case supported[3]:
+			info.Standard = StandardERC1155
+			info.Metadata = supported[4]
+		}
+		info.Royalty = supported[5]
+		info.ERC4906 = supported[6]
+		info.ERC5192 = supported[7]
+		if info.Standard != StandardUnknown {
+			return info, nil
+		}
+	}
+
+	var zero common.Address
+	results, err := probeContract(ctx, cli, address, []probeCall{
+		{erc20ABI, "totalSupply", nil},
+		{erc20ABI, "balanceOf", []interface{}{zero}},
+		{erc20ABI, "name", nil},
+		{erc20ABI, "symbol", nil},
+		{erc20ABI, "decimals", nil},
+		{standardProbeABI, "asset", nil},
+		{standardProbeABI, "totalAssets", nil},
+		{standardProbeABI, "DOMAIN_SEPARATOR", nil},
+		{standardProbeABI, "nonces", []interface{}{zero}},
+	}, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if results[0] == nil || results[1] == nil {
+		return info, nil
+	}
+	info.Standard = StandardERC20
+	info.Metadata = results[2] != nil && results[3] != nil && results[4] != nil
+	info.ERC4626 = results[5] != nil && results[6] != nil
+	info.Permit = results[7] != nil && results[8] != nil
+	return info, nil
+}
+
+// probeContract executes calls against contract in one batch. The result of a call that reverted or
+// returned undecodable data is nil.
+func probeContract(ctx context.Context, cli *ethclient.Client, contract string, calls []probeCall, blockNumber *big.Int) ([][]interface{}, error) {
+	to := common.HexToAddress(contract)
+	msgs := make([]ethereum.CallMsg, len(calls))
+	for i, call := range calls {
+		data, err := call.ins.Pack(call.method, call.args...)
+		if err != nil {
+			return nil, err
+		}
+		msgs[i] = ethereum.CallMsg{To: &to, Data: data}
+	}
+	results, err := BatchCallContract(ctx, cli, msgs, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([][]interface{}, len(calls))
+	for i, result := range results {
+		if result.Err != nil {
+			if !isCallFailure(result.Err) {
+				return nil, result.Err
+			}
+			continue
+		}
+		values, err := calls[i].ins.Unpack(calls[i].method, result.Data)
+		if err != nil || len(values) == 0 {
+			continue
+		}
+		out[i] = values
+	}
+	return out, nil
+}
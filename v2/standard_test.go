@@ -0,0 +1,116 @@
+package ethcli
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func Test_DetectStandard(t *testing.T) {
+	var (
+		nft     = common.HexToAddress("0x5000000000000000000000000000000000000001")
+		multi   = common.HexToAddress("0x5000000000000000000000000000000000000002")
+		vault   = common.HexToAddress("0x5000000000000000000000000000000000000003")
+		sloppy  = common.HexToAddress("0x5000000000000000000000000000000000000004")
+		other   = common.HexToAddress("0x5000000000000000000000000000000000000005")
+		account = common.HexToAddress("0x5000000000000000000000000000000000000006")
+	)
+	interfaces := map[common.Address][][4]byte{
+		nft:   {InterfaceIdERC165, InterfaceIdERC721, InterfaceIdERC721Metadata, InterfaceIdERC2981, InterfaceIdERC5192},
+		multi: {InterfaceIdERC165, InterfaceIdERC1155, InterfaceIdERC1155MetadataURI, InterfaceIdERC4906},
+	}
+	erc20 := func(to common.Address, values ...interface{}) ([]interface{}, *mockError) {
+		if to != vault && to != sloppy {
+			return nil, mockRevert
+		}
+		return values, nil
+	}
+
+	rpc := newMockRPC(t, map[string]mockHandler{
+		"eth_getCode": func(params []json.RawMessage) (interface{}, *mockError) {
+			var address common.Address
+			_ = json.Unmarshal(params[0], &address)
+			if address == account {
+				return hexutil.Bytes{}, nil
+			}
+			return hexutil.Bytes{0x60, 0x80}, nil
+		},
+		"eth_call": mockContracts([]string{customERC721SupportsInterface, openzeppelinERC20Abi, standardProbeAbi}, map[string]mockMethod{
+			"supportsInterface": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				id := args[0].([4]byte)
+				if to == sloppy {
+					// claims support for everything, including 0xffffffff
+					return []interface{}{true}, nil
+				}
+				for _, v := range interfaces[to] {
+					if v == id {
+						return []interface{}{true}, nil
+					}
+				}
+				if _, ok := interfaces[to]; ok {
+					return []interface{}{false}, nil
+				}
+				return nil, mockRevert
+			},
+			"totalSupply": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return erc20(to, big.NewInt(1000))
+			},
+			"balanceOf": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return erc20(to, big.NewInt(0))
+			},
+			"name": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return erc20(to, "Vault")
+			},
+			"symbol": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return erc20(to, "V")
+			},
+			"decimals": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return erc20(to, uint8(18))
+			},
+			"asset": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				if to != vault {
+					return nil, mockRevert
+				}
+				return []interface{}{exampleToken}, nil
+			},
+			"totalAssets": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				if to != vault {
+					return nil, mockRevert
+				}
+				return []interface{}{big.NewInt(1000)}, nil
+			},
+			"DOMAIN_SEPARATOR": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return erc20(to, [32]byte{1})
+			},
+			"nonces": func(to common.Address, args []interface{}) ([]interface{}, *mockError) {
+				return erc20(to, big.NewInt(0))
+			},
+		}),
+	})
+	cli := rpc.client(t)
+
+	tests := []struct {
+		address common.Address
+		want    StandardInfo
+	}{
+		{nft, StandardInfo{Standard: StandardERC721, HasCode: true, ERC165: true, Metadata: true, Royalty: true, ERC5192: true}},
+		{multi, StandardInfo{Standard: StandardERC1155, HasCode: true, ERC165: true, Metadata: true, ERC4906: true}},
+		{vault, StandardInfo{Standard: StandardERC20, HasCode: true, Metadata: true, ERC4626: true, Permit: true}},
+		{sloppy, StandardInfo{Standard: StandardERC20, HasCode: true, Metadata: true, Permit: true}},
+		{other, StandardInfo{HasCode: true}},
+		{account, StandardInfo{}},
+	}
+	for _, tt := range tests {
+		got, err := DetectStandard(context.Background(), cli, tt.address.Hex(), nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.address.Hex(), err)
+		}
+		if *got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.address.Hex(), *got, tt.want)
+		}
+	}
+}